@@ -0,0 +1,194 @@
+package xbindata
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// AccessorsConst generates a constant for each asset and directory name.
+	AccessorsConst = "const"
+	// AccessorsTree generates a nested struct tree mirroring the assets
+	// directories.
+	AccessorsTree = "tree"
+)
+
+var (
+	regIdentSep = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+	identInitialisms = map[string]bool{
+		"api": true, "css": true, "csv": true, "gif": true, "html": true,
+		"http": true, "ico": true, "id": true, "jpeg": true, "jpg": true,
+		"js": true, "json": true, "pdf": true, "png": true, "sql": true,
+		"svg": true, "ttf": true, "url": true, "xml": true, "yaml": true,
+		"yml": true,
+	}
+
+	// generatedApiNames are the exported names of the generated package
+	// API, reserved from top level accessors.
+	generatedApiNames = []string{
+		"Assets", "DefaultFS", "FS", "HashedName", "Integrity", "Load",
+		"LoadDefault", "OnFsLoad", "OpenOutlined", "Original", "Outlined",
+		"OutlinedPath", "OutlinedReaderFactory", "StartPos", "TestXbAssets",
+		"TestXbFS", "TestXbTree",
+	}
+)
+
+// exportedIdent converts the given name into a exported identifier.
+// Words are capitalized and known initialisms are upper cased, example:
+// `app.min.css` is converted to `AppMinCSS`.
+func exportedIdent(name string, known map[string]int) string {
+	var buf bytes.Buffer
+	for _, word := range regIdentSep.Split(name, -1) {
+		if word == "" {
+			continue
+		}
+		if identInitialisms[strings.ToLower(word)] {
+			buf.WriteString(strings.ToUpper(word))
+		} else {
+			buf.WriteString(strings.ToUpper(word[0:1]) + word[1:])
+		}
+	}
+
+	name = buf.String()
+
+	// Identifier must starts with an upper letter.
+	if name == "" || !unicode.IsUpper(rune(name[0])) {
+		name = "X" + name
+	}
+
+	return uniqueName(name, known)
+}
+
+// writeAccessors writes the typed asset name accessors.
+func writeAccessors(w io.Writer, c *Config, toc []Asset, knownFuncs map[string]int) (err error) {
+	tree := newAssetTree()
+	for i := range toc {
+		tree.Add(strings.Split(toc[i].OriginalName(), "/"), toc[i])
+	}
+
+	var buf bytes.Buffer
+	if c.Accessors == AccessorsConst {
+		writeAccessorsConst(&buf, tree, knownFuncs)
+	} else {
+		writeAccessorsTree(&buf, tree, knownFuncs)
+	}
+
+	fmted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(fmted)
+	return
+}
+
+func (node *assetTree) sortedNames() (names []string) {
+	for name := range node.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// accessorsKnown returns the known names of top level accessors: the
+// generated package API and the asset variables and readers.
+func accessorsKnown(knownFuncs map[string]int) map[string]int {
+	known := map[string]int{}
+	for _, name := range generatedApiNames {
+		known[name] = 2
+	}
+	for name := range knownFuncs {
+		known[name] = 2
+		known[name+"Reader"] = 2
+	}
+	return known
+}
+
+func writeAccessorsConst(buf *bytes.Buffer, tree *assetTree, knownFuncs map[string]int) {
+	var (
+		known        = accessorsKnown(knownFuncs)
+		assets, dirs []string
+		walk         func(node *assetTree)
+	)
+
+	walk = func(node *assetTree) {
+		for _, name := range node.sortedNames() {
+			child := node.Children[name]
+			if child.Asset.Func != "" {
+				assets = append(assets, fmt.Sprintf("\t%s = %q\n", exportedIdent("Asset/"+child.Path, known), child.Path))
+			} else {
+				dirs = append(dirs, fmt.Sprintf("\t%s = %q\n", exportedIdent("Dir/"+child.Path, known), child.Path))
+				walk(child)
+			}
+		}
+	}
+	walk(tree)
+
+	buf.WriteString("\n// Asset names.\nconst (\n")
+	buf.WriteString(strings.Join(assets, ""))
+	buf.WriteString(")\n")
+
+	if len(dirs) > 0 {
+		buf.WriteString("\n// Asset directory names.\nconst (\n")
+		buf.WriteString(strings.Join(dirs, ""))
+		buf.WriteString(")\n")
+	}
+}
+
+func writeAccessorsTree(buf *bytes.Buffer, tree *assetTree, knownFuncs map[string]int) {
+	var (
+		top   = accessorsKnown(knownFuncs)
+		types bytes.Buffer
+		dir   func(node *assetTree) (typ, value string)
+	)
+
+	dir = func(node *assetTree) (typ, value string) {
+		var (
+			known          = map[string]int{"Path": 2}
+			fields, values []string
+		)
+		typ = uniqueName(safeFunctionName(node.Path+"/dir", knownFuncs), top)
+		for _, name := range node.sortedNames() {
+			var (
+				child = node.Children[name]
+				id    = exportedIdent(name, known)
+			)
+			if child.Asset.Func != "" {
+				fields = append(fields, id+" string")
+				values = append(values, fmt.Sprintf("%s: %q", id, child.Path))
+			} else {
+				ctyp, cvalue := dir(child)
+				fields = append(fields, id+" "+ctyp)
+				values = append(values, id+": "+cvalue)
+			}
+		}
+		fmt.Fprintf(&types, "\n// %s is the %q assets directory accessor.\ntype %s struct {\n\tPath string\n\t%s\n}\n",
+			typ, node.Path, typ, strings.Join(fields, "\n\t"))
+		values = append([]string{fmt.Sprintf("Path: %q", node.Path)}, values...)
+		value = fmt.Sprintf("%s{\n%s,\n}", typ, strings.Join(values, ",\n"))
+		return
+	}
+
+	for _, name := range tree.sortedNames() {
+		var (
+			child = tree.Children[name]
+			id    = exportedIdent(name, top)
+		)
+		// the next directory types
+		knownFuncs[id] = 2
+		if child.Asset.Func != "" {
+			fmt.Fprintf(buf, "\n// %s is the %q asset name.\nconst %s = %q\n", id, child.Path, id, child.Path)
+		} else {
+			_, value := dir(child)
+			fmt.Fprintf(buf, "\n// %s is the %q assets directory accessor.\nvar %s = %s\n", id, child.Path, id, value)
+		}
+	}
+
+	buf.Write(types.Bytes())
+}
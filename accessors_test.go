@@ -0,0 +1,155 @@
+package xbindata
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestExportedIdent(t *testing.T) {
+	known := map[string]int{}
+	for _, tt := range []struct{ name, ident string }{
+		{"app.min.css", "AppMinCSS"},
+		{"img/logo_id.svg", "ImgLogoIDSVG"},
+		{"1st.txt", "X1stTxt"},
+		{"---", "X"},
+		{"app-min.css", "AppMinCSS2"},
+		{"app_min.css", "AppMinCSS3"},
+	} {
+		if ident := exportedIdent(tt.name, known); ident != tt.ident {
+			t.Errorf("%s: have %s, want %s", tt.name, ident, tt.ident)
+		}
+	}
+}
+
+func TestWriteAccessors(t *testing.T) {
+	var (
+		toc []Asset
+		fn  = map[string]int{}
+	)
+	for _, name := range []string{"assets/a.txt", "a.txt", "a_txt", "load.js", "dir/path", "dir/sub/b.css", ".hidden"} {
+		toc = append(toc, Asset{Name: name, Func: safeFunctionName(name, fn)})
+	}
+
+	for mode, want := range map[string][]string{
+		AccessorsConst: {
+			"AssetATxt = \"a.txt\"",
+			"AssetATxt2 = \"a_txt\"",
+			"AssetAssetsATxt = \"assets/a.txt\"",
+			"AssetDirSubBCSS = \"dir/sub/b.css\"",
+			"AssetHidden = \".hidden\"",
+			"DirDirSub = \"dir/sub\"",
+		},
+		AccessorsTree: {
+			"const ATxt = \"a.txt\"",
+			"const ATxt2 = \"a_txt\"",
+			// reserved by the generated API
+			"var Assets2 = ",
+			"const LoadJS = \"load.js\"",
+			// the `Hidden` asset variable
+			"const Hidden2 = \".hidden\"",
+			"Path2 string",
+			"Path2: \"dir/path\"",
+			"BCSS: \"dir/sub/b.css\"",
+		},
+	} {
+		var buf bytes.Buffer
+		known := map[string]int{}
+		for name, n := range fn {
+			known[name] = n
+		}
+		if err := writeAccessors(&buf, &Config{Accessors: mode}, toc, known); err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		// without the alignment spaces
+		out := regexp.MustCompile(`[ \t]+`).ReplaceAllString(buf.String(), " ")
+		for _, s := range want {
+			if !strings.Contains(out, s) {
+				t.Errorf("%s: %q not found in\n%s", mode, s, out)
+			}
+		}
+	}
+}
+
+func TestAccessorsTreeBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbaccessors")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{".hidden", ".git/config", "hidden-reader"} {
+		pth := filepath.Join(dir, "static", name)
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewMemOutputFS()
+	b, err := NewBuilder(
+		WithDir(dir),
+		WithPackage("assets"),
+		WithOutput("assets.go"),
+		WithInput(InputConfig{Path: "static", Recursive: true}),
+		WithPrefix("static"),
+		WithAccessors(AccessorsTree),
+		WithOutputFS(fs),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = b.Build(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := fs.ReadFile(filepath.Join(dir, "assets.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the redeclared names breaks the build
+	f, err := parser.ParseFile(token.NewFileSet(), "assets.go", data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	declared := map[string]bool{}
+	declare := func(name string) {
+		if name != "_" && name != "init" && declared[name] {
+			t.Errorf("%s redeclared", name)
+		}
+		declared[name] = true
+	}
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				declare(decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						declare(name.Name)
+					}
+				case *ast.TypeSpec:
+					declare(spec.Name.Name)
+				}
+			}
+		}
+	}
+	for _, name := range []string{"Hidden", "Hidden2", "HiddenReader", "Git"} {
+		if !declared[name] {
+			t.Errorf("%s not declared", name)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package xbindata
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moisespsena-go/xbindata/walker"
+)
+
+// ArchiveCacheDir returns the directory of extracted archive inputs.
+// Defaults to `xbindata/archives` into the user cache directory, or the
+// XB_ARCHIVE_CACHE_DIR environment variable value if defined.
+var ArchiveCacheDir = func() (dir string, err error) {
+	if dir = os.Getenv("XB_ARCHIVE_CACHE_DIR"); dir != "" {
+		return
+	}
+	if dir, err = os.UserCacheDir(); err != nil {
+		return
+	}
+	return filepath.Join(dir, "xbindata", "archives"), nil
+}
+
+// IsArchiveInput returns if pth is a supported archive input: `.zip`,
+// `.tar`, `.tar.gz` or `.tgz` file.
+func IsArchiveInput(pth string) bool {
+	return archiveFormat(pth) != ""
+}
+
+func archiveFormat(pth string) string {
+	lower := strings.ToLower(pth)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// walkArchive walks the archive entries as if it were a directory. The
+// archive is extracted once into the ArchiveCacheDir, keeping the entries
+// mode and modification time. The walked paths are `ARCHIVE/ENTRY`, with
+// the extracted file as the real path. The archive symlinks must be relative
+// and resolve into the archive, so the Symlinks policy never reads host
+// files.
+func (i InputConfig) walkArchive(visited *map[string]bool, recursive bool, cb walker.WalkCallback) (err error) {
+	var dir string
+	if dir, err = extractArchive(i.Path); err != nil {
+		return fmt.Errorf("extract archive %q failed: %v", i.Path, err)
+	}
+
+	root := dir
+	if i.Subdir != "" {
+		subdir := path.Clean("/" + filepath.ToSlash(i.Subdir))
+		root = filepath.Join(dir, filepath.FromSlash(subdir))
+	}
+
+	w := walker.Walker{Recursive: recursive, VisitedPaths: visited, Symlinks: i.Symlinks, Dirs: i.dirs}
+	return w.Walk(root, i.prepareCb(func(info walker.FileInfo) error {
+		rel, err := filepath.Rel(root, info.Path)
+		if err != nil {
+			return err
+		}
+		info.RealPath = info.Path
+		info.Path = filepath.Join(i.Path, rel)
+		return cb(info)
+	}))
+}
+
+// extractArchive extracts the archive into the cache directory and
+// returns it. The cache directory key is the archive path, size and
+// modification time, so a changed archive is extracted again, and the
+// previous extraction of archive path is removed.
+func extractArchive(pth string) (dir string, err error) {
+	if pth, err = filepath.Abs(pth); err != nil {
+		return
+	}
+	var info os.FileInfo
+	if info, err = os.Stat(pth); err != nil {
+		return
+	}
+	var cacheDir string
+	if cacheDir, err = ArchiveCacheDir(); err != nil {
+		return
+	}
+
+	pthKey := sha256.Sum256([]byte(pth))
+	key := sha256.Sum256([]byte(pth + "\x00" + strconv.FormatInt(info.Size(), 10) + "\x00" + info.ModTime().UTC().String()))
+	prefix := hex.EncodeToString(pthKey[:8]) + "-"
+	dir = filepath.Join(cacheDir, prefix+hex.EncodeToString(key[:16]))
+	if _, err = os.Stat(dir); err == nil {
+		return
+	} else if !os.IsNotExist(err) {
+		return
+	}
+
+	if err = os.MkdirAll(cacheDir, 0755); err != nil {
+		return
+	}
+	var tmp string
+	if tmp, err = ioutil.TempDir(cacheDir, ".extract"); err != nil {
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	log.Printf("extracting archive `%s` to `%s`\n", pth, dir)
+
+	var x archiveExtractor
+	if archiveFormat(pth) == ".zip" {
+		err = x.unzip(tmp, pth)
+	} else {
+		err = x.untar(tmp, pth)
+	}
+	if err == nil {
+		err = x.checkLinks(tmp)
+	}
+	if err == nil {
+		err = x.setDirsMetadata()
+	}
+	if err != nil {
+		return
+	}
+
+	if err = os.Rename(tmp, dir); err != nil {
+		if _, err2 := os.Stat(dir); err2 == nil {
+			// extracted by other process
+			err = nil
+		}
+	}
+	if err == nil {
+		pruneArchiveCache(cacheDir, prefix, dir)
+	}
+	return
+}
+
+// pruneArchiveCache removes the extractions of cacheDir with the prefix
+// (the archive path key), except the current dir.
+func pruneArchiveCache(cacheDir, prefix, dir string) {
+	olds, _ := filepath.Glob(filepath.Join(cacheDir, prefix+"*"))
+	for _, old := range olds {
+		if old != dir {
+			if err := os.RemoveAll(old); err != nil {
+				log.Printf("remove the previous extraction `%s` failed: %v\n", old, err)
+			}
+		}
+	}
+}
+
+func isArchiveRoot(name string) bool {
+	return path.Clean("/"+filepath.ToSlash(name)) == "/"
+}
+
+type archiveExtractorDir struct {
+	pth     string
+	mode    os.FileMode
+	modTime time.Time
+}
+
+type archiveExtractor struct {
+	dirs []archiveExtractorDir
+	// links are the created symlinks.
+	links []string
+}
+
+// entryPath returns the destination path of archive entry. Returns an
+// error if the entry escapes the destination directory.
+func (archiveExtractor) entryPath(dst, name string) (string, error) {
+	clean := path.Clean("/" + filepath.ToSlash(name))
+	if strings.Contains(name, "\x00") {
+		return "", fmt.Errorf("invalid archive entry name %q", name)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(name), "/") {
+		if part == ".." {
+			return "", fmt.Errorf("archive entry %q escapes the destination", name)
+		}
+	}
+	return filepath.Join(dst, filepath.FromSlash(clean)), nil
+}
+
+// checkParents returns an error if the parent directories of destination
+// path pth, into dst, or pth itself are symlinks. So the entries are not
+// written through the symlinks created by previous entries.
+func (archiveExtractor) checkParents(dst, pth string) error {
+	rel, err := filepath.Rel(dst, pth)
+	if err != nil {
+		return err
+	}
+	cur := dst
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		cur = filepath.Join(cur, part)
+		info, err := os.Lstat(cur)
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%q is a symlink", filepath.ToSlash(strings.TrimPrefix(cur, dst)))
+		}
+	}
+	return nil
+}
+
+func (x *archiveExtractor) dir(dst, pth string, mode os.FileMode, modTime time.Time) error {
+	if err := x.checkParents(dst, pth); err != nil {
+		return err
+	}
+	x.dirs = append(x.dirs, archiveExtractorDir{pth, mode.Perm() | 0700, modTime})
+	return os.MkdirAll(pth, 0755)
+}
+
+func (x *archiveExtractor) file(dst, pth string, mode os.FileMode, modTime time.Time, r io.Reader) (err error) {
+	if err = x.checkParents(dst, pth); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return
+	}
+	var f *os.File
+	if f, err = os.OpenFile(pth, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0600); err != nil {
+		return
+	}
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return
+	}
+	if err = f.Close(); err != nil {
+		return
+	}
+	return os.Chtimes(pth, modTime, modTime)
+}
+
+// symlink creates the symlink. Returns an error if the target is absolute
+// or escapes the destination directory.
+func (x *archiveExtractor) symlink(dst, pth, target string) (err error) {
+	var rel string
+	if rel, err = filepath.Rel(dst, pth); err != nil {
+		return
+	}
+	slashed := filepath.ToSlash(target)
+	if target == "" || path.IsAbs(slashed) || filepath.IsAbs(target) || filepath.VolumeName(target) != "" {
+		return fmt.Errorf("absolute symlink target %q", target)
+	}
+	if to := path.Join(path.Dir(filepath.ToSlash(rel)), slashed); to == ".." || strings.HasPrefix(to, "../") {
+		return fmt.Errorf("symlink target %q escapes the destination", target)
+	}
+	if err = x.checkParents(dst, pth); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return
+	}
+	x.links = append(x.links, pth)
+	return os.Symlink(target, pth)
+}
+
+// checkLinks returns an error if any symlink resolves outside of dst,
+// through the other symlinks. The dangling symlinks target directory must
+// resolve into dst.
+func (x *archiveExtractor) checkLinks(dst string) (err error) {
+	if dst, err = filepath.EvalSymlinks(dst); err != nil {
+		return
+	}
+	for _, pth := range x.links {
+		real, err := filepath.EvalSymlinks(pth)
+		if os.IsNotExist(err) {
+			var target string
+			if target, err = os.Readlink(pth); err == nil {
+				real, err = filepath.EvalSymlinks(filepath.Dir(filepath.Join(filepath.Dir(pth), target)))
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("symlink %q: %v", filepath.ToSlash(strings.TrimPrefix(pth, dst)), err)
+		}
+		if real != dst && !strings.HasPrefix(real, dst+string(filepath.Separator)) {
+			return fmt.Errorf("symlink %q escapes the destination", filepath.ToSlash(strings.TrimPrefix(pth, dst)))
+		}
+	}
+	return
+}
+
+// setDirsMetadata sets the directories mode and modification time, after
+// the children creation.
+func (x *archiveExtractor) setDirsMetadata() (err error) {
+	for i := len(x.dirs) - 1; i >= 0; i-- {
+		d := x.dirs[i]
+		if err = os.Chmod(d.pth, d.mode); err != nil {
+			return
+		}
+		if !d.modTime.IsZero() {
+			if err = os.Chtimes(d.pth, d.modTime, d.modTime); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+func (x *archiveExtractor) unzip(dst, pth string) (err error) {
+	var zr *zip.ReadCloser
+	if zr, err = zip.OpenReader(pth); err != nil {
+		return
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if isArchiveRoot(f.Name) {
+			continue
+		}
+		var entryPth string
+		if entryPth, err = x.entryPath(dst, f.Name); err != nil {
+			return
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = x.dir(dst, entryPth, mode, f.Modified)
+		default:
+			var r io.ReadCloser
+			if r, err = f.Open(); err != nil {
+				return
+			}
+			if mode&os.ModeSymlink != 0 {
+				var target []byte
+				if target, err = ioutil.ReadAll(r); err == nil {
+					err = x.symlink(dst, entryPth, string(target))
+				}
+			} else {
+				err = x.file(dst, entryPth, mode, f.Modified, r)
+			}
+			r.Close()
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", f.Name, err)
+		}
+	}
+	return
+}
+
+func (x *archiveExtractor) untar(dst, pth string) (err error) {
+	var f *os.File
+	if f, err = os.Open(pth); err != nil {
+		return
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if format := archiveFormat(pth); format == ".tar.gz" || format == ".tgz" {
+		var gr *gzip.Reader
+		if gr, err = gzip.NewReader(f); err != nil {
+			return
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		var h *tar.Header
+		if h, err = tr.Next(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return
+		}
+		if isArchiveRoot(h.Name) {
+			continue
+		}
+		var entryPth string
+		if entryPth, err = x.entryPath(dst, h.Name); err != nil {
+			return
+		}
+		switch h.Typeflag {
+		case tar.TypeDir:
+			err = x.dir(dst, entryPth, h.FileInfo().Mode(), h.ModTime)
+		case tar.TypeReg:
+			err = x.file(dst, entryPth, h.FileInfo().Mode(), h.ModTime, tr)
+		case tar.TypeSymlink:
+			err = x.symlink(dst, entryPth, h.Linkname)
+		default:
+			// hard links, devices and other special entries are ignored
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", h.Name, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package xbindata
+
+import (
+	"archive/tar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractArchive(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "xbarchive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	os.Setenv("XB_ARCHIVE_CACHE_DIR", filepath.Join(tmp, "cache"))
+	defer os.Unsetenv("XB_ARCHIVE_CACHE_DIR")
+
+	escape := filepath.Join(tmp, "escape")
+	if err = os.Mkdir(escape, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	type entry struct{ name, link, data string }
+	writeTar := func(name string, entries ...entry) string {
+		pth := filepath.Join(tmp, name+".tar")
+		f, err := os.Create(pth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		tw := tar.NewWriter(f)
+		for _, e := range entries {
+			h := &tar.Header{Name: e.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(e.data))}
+			if e.link != "" {
+				h.Typeflag, h.Linkname, h.Size = tar.TypeSymlink, e.link, 0
+			}
+			if err = tw.WriteHeader(h); err != nil {
+				t.Fatal(err)
+			}
+			tw.Write([]byte(e.data))
+		}
+		if err = tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return pth
+	}
+
+	for _, tt := range []struct {
+		name    string
+		entries []entry
+		err     string
+	}{
+		{"absolute", []entry{{name: "secret", link: "/etc/hostname"}}, "absolute symlink target"},
+		{"escape", []entry{{name: "d/secret", link: "../../escape"}}, "escapes the destination"},
+		{"chain", []entry{{name: "s", link: "."}, {name: "l", link: "s/.."}}, `symlink "/l" escapes the destination`},
+		{"parent", []entry{{name: "d/x", data: "x"}, {name: "a", link: "d"}, {name: "a/pwned.txt", data: "pwned"}}, `"/a" is a symlink`},
+		{"overwrite", []entry{{name: "d/x", data: "x"}, {name: "a", link: "d/x"}, {name: "a", data: "pwned"}}, `"/a" is a symlink`},
+	} {
+		if _, err := extractArchive(writeTar(tt.name, tt.entries...)); err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: have error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+	if files, _ := ioutil.ReadDir(escape); len(files) > 0 {
+		t.Errorf("file written outside of cache: %s", files[0].Name())
+	}
+
+	dir, err := extractArchive(writeTar("valid", entry{name: "d/f.txt", data: "f"}, entry{name: "l", link: "d/f.txt"}, entry{name: "dl", link: "d"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "dl", "f.txt")); err != nil || string(data) != "f" {
+		t.Errorf("bad link data %q: %v", data, err)
+	}
+}
+
+func TestExtractArchivePrune(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "xbarchive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	cache := filepath.Join(tmp, "cache")
+	os.Setenv("XB_ARCHIVE_CACHE_DIR", cache)
+	defer os.Unsetenv("XB_ARCHIVE_CACHE_DIR")
+
+	writeTar := func(name, data string, modTime time.Time) {
+		f, err := os.Create(filepath.Join(tmp, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tw := tar.NewWriter(f)
+		if err = tw.WriteHeader(&tar.Header{Name: "f.txt", Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(data))
+		tw.Close()
+		f.Close()
+		if err = os.Chtimes(f.Name(), modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	extract := func(name string) string {
+		dir, err := extractArchive(filepath.Join(tmp, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	writeTar("a.tar", "a", time.Unix(1577934245, 0))
+	writeTar("b.tar", "b", time.Unix(1577934245, 0))
+	a1, b := extract("a.tar"), extract("b.tar")
+	writeTar("a.tar", "a2", time.Unix(1577934246, 0))
+	a2 := extract("a.tar")
+	if a1 == a2 {
+		t.Fatal("the changed archive isn't extracted again")
+	}
+	for dir, exists := range map[string]bool{a1: false, a2: true, b: true} {
+		if _, err := os.Stat(dir); os.IsNotExist(err) == exists {
+			t.Errorf("%s: want exists=%v, have error %v", filepath.Base(dir), exists, err)
+		}
+	}
+}
+
+func TestArchiveSubdirValidate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbarchive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewConfig()
+	c.Input = []InputConfig{{Path: dir, Subdir: "sub"}}
+	if err = c.validate(); err == nil || !strings.Contains(err.Error(), "requires an archive") {
+		t.Errorf("have error %v, want subdir error", err)
+	}
+}
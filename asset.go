@@ -5,9 +5,13 @@
 package xbindata
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/moisespsena-go/xbindata/digest"
@@ -28,6 +32,29 @@ type Asset struct {
 	ctime time.Time
 
 	digest *[sha256.Size]byte
+
+	// storedSize is the compressed size. Zero if not compressed.
+	storedSize int64
+
+	// reader is the data reader expression of sharded asset.
+	reader string
+
+	hashedName   string
+	originalName string
+
+	// override is true if asset is from an override input.
+	override bool
+
+	// link is the symlink target of a preserved symlink.
+	link string
+
+	// dir is true if is a directory entry.
+	dir bool
+
+	metadata map[string]string
+
+	// data are the contents of in-memory asset, instead of Path file.
+	data []byte
 }
 
 func (a *Asset) Info() (info os.FileInfo, err error) {
@@ -35,7 +62,11 @@ func (a *Asset) Info() (info os.FileInfo, err error) {
 		return a.info, nil
 	}
 
-	if a.info, err = os.Stat(a.Path); err != nil {
+	stat := os.Stat
+	if a.link != "" {
+		stat = os.Lstat
+	}
+	if a.info, err = stat(a.Path); err != nil {
 		return
 	}
 	t := times.Get(a.info)
@@ -52,13 +83,18 @@ func (a *Asset) InfoExport(c *Config) (string, error) {
 	modTime := fi.ModTime().Unix()
 	changeTime := a.ctime.Unix()
 	size := fi.Size()
+	if a.link != "" || a.dir {
+		size = 0
+	}
 	if c.NoMetadata {
 		mode = 0
 		modTime = 0
 		size = 0
 		changeTime = 0
 	}
-	if c.Mode > 0 {
+	if a.dir {
+		mode |= uint(os.ModeDir)
+	} else if c.Mode > 0 {
 		mode = uint(os.ModePerm) & c.Mode
 	}
 	if c.ChangeTime > 0 {
@@ -82,8 +118,27 @@ func (a *Asset) SourceCode(c *Config, start int64) (code string, err error) {
 	if err != nil {
 		return "", err
 	}
-	return fmt.Sprintf("bc.NewFile(bc.NewFileInfo(%q, %s), %s,  func()[sha256.Size]byte{return %#v})",
-		a.Name, info, readerFunc, *digest), nil
+	return fmt.Sprintf("bc.NewFile(bc.NewFileInfo(%q, %s)%s, %s,  func()[sha256.Size]byte{return %#v})",
+		a.Name, info, a.infoSetters(), readerFunc, *digest), nil
+}
+
+// infoSetters returns the setter calls of generated file info.
+func (a *Asset) infoSetters() (s string) {
+	if a.link != "" {
+		s = fmt.Sprintf(".SetLink(%q)", a.link)
+	}
+	return s + a.metadataExport()
+}
+
+// Open opens the asset contents. Symlinks does not have contents.
+func (a *Asset) Open() (io.ReadCloser, error) {
+	if a.link != "" {
+		return ioutil.NopCloser(strings.NewReader("")), nil
+	}
+	if a.data != nil {
+		return ioutil.NopCloser(bytes.NewReader(a.data)), nil
+	}
+	return os.Open(a.Path)
 }
 
 func (a *Asset) Digest() (dig *[sha256.Size]byte, err error) {
@@ -91,7 +146,13 @@ func (a *Asset) Digest() (dig *[sha256.Size]byte, err error) {
 		return a.digest, nil
 	}
 
-	if dig, err = digest.Digest(a.Path); err != nil {
+	if a.link != "" {
+		d := sha256.Sum256(nil)
+		dig = &d
+	} else if a.data != nil {
+		d := sha256.Sum256(a.data)
+		dig = &d
+	} else if dig, err = digest.Digest(a.Path); err != nil {
 		return
 	}
 	a.digest = dig
@@ -43,7 +43,7 @@ func BenchmarkBindata(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if err := bindata.Translate(cfg); err != nil {
+		if _, err := bindata.Translate(cfg); err != nil {
 			b.Fatal(err)
 		}
 	}
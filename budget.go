@@ -0,0 +1,156 @@
+package xbindata
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dustin/go-humanize"
+)
+
+// BudgetLimits defines the size limits of a set of assets. Zero values are
+// unlimited.
+type BudgetLimits struct {
+	// MaxTotalSize is the max sum of asset sizes in bytes.
+	MaxTotalSize int64
+	// MaxFileSize is the max size in bytes of a single asset.
+	MaxFileSize int64
+	// MaxFiles is the max count of assets.
+	MaxFiles int
+}
+
+func (l BudgetLimits) IsZero() bool {
+	return l.MaxTotalSize == 0 && l.MaxFileSize == 0 && l.MaxFiles == 0
+}
+
+// Budget defines the size limits of the package assets and, optionally,
+// of the assets into each name space (the first parts of the asset name).
+type Budget struct {
+	BudgetLimits
+	NameSpaces map[string]BudgetLimits
+}
+
+// BudgetViolation holds a exceeded limit and the offending assets.
+type BudgetViolation struct {
+	// NameSpace is the violated name space. Blank for the package limits.
+	NameSpace string
+	// Limit is the limit name: max_total_size, max_file_size or max_files.
+	Limit  string
+	Max    int64
+	Actual int64
+	Assets []*Asset
+}
+
+func (v *BudgetViolation) scope() string {
+	if v.NameSpace == "" {
+		return "."
+	}
+	return v.NameSpace
+}
+
+func (v *BudgetViolation) String() string {
+	if v.Limit == "max_files" {
+		return fmt.Sprintf("%s: %s exceeded: %d > %d", v.scope(), v.Limit, v.Actual, v.Max)
+	}
+	return fmt.Sprintf("%s: %s exceeded: %s > %s", v.scope(), v.Limit,
+		humanize.IBytes(uint64(v.Actual)), humanize.IBytes(uint64(v.Max)))
+}
+
+// BudgetError is returned by Translate when the assets exceeds the budget.
+type BudgetError struct {
+	Package    string
+	Violations []*BudgetViolation
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("package %q exceeds the size budget:\n%s", e.Package, e.Table())
+}
+
+// Table returns the violations and the offending assets as text table.
+func (e *BudgetError) Table() string {
+	var (
+		buf bytes.Buffer
+		tw  = tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	)
+	for _, v := range e.Violations {
+		fmt.Fprintln(tw, v.String())
+		fmt.Fprintln(tw, "\tNAME\tSIZE\tPATH")
+		for _, a := range v.Assets {
+			fmt.Fprintf(tw, "\t%s\t%s\t%s\n", a.Name, humanize.IBytes(uint64(a.Size)), a.Path)
+		}
+	}
+	tw.Flush()
+	return buf.String()
+}
+
+// budgetMaxListed is the max count of assets listed by total size and
+// files count violations.
+const budgetMaxListed = 10
+
+// Check checks the toc assets and returns a *BudgetError if any limit
+// was exceeded.
+func (b *Budget) Check(pkg string, toc []Asset) error {
+	var violations []*BudgetViolation
+
+	check := func(ns string, l BudgetLimits) {
+		var (
+			assets []*Asset
+			total  int64
+		)
+		for i := range toc {
+			if ns == "" || toc[i].Name == ns || strings.HasPrefix(toc[i].Name, ns+"/") {
+				assets = append(assets, &toc[i])
+				total += toc[i].Size
+			}
+		}
+
+		// largest first
+		sort.SliceStable(assets, func(i, j int) bool {
+			return assets[i].Size > assets[j].Size
+		})
+
+		largest := assets
+		if len(largest) > budgetMaxListed {
+			largest = largest[0:budgetMaxListed]
+		}
+
+		if l.MaxFileSize > 0 {
+			var offending []*Asset
+			for _, a := range assets {
+				if a.Size > l.MaxFileSize {
+					offending = append(offending, a)
+				}
+			}
+			if len(offending) > 0 {
+				violations = append(violations, &BudgetViolation{ns, "max_file_size", l.MaxFileSize, offending[0].Size, offending})
+			}
+		}
+		if l.MaxTotalSize > 0 && total > l.MaxTotalSize {
+			violations = append(violations, &BudgetViolation{ns, "max_total_size", l.MaxTotalSize, total, largest})
+		}
+		if l.MaxFiles > 0 && len(assets) > l.MaxFiles {
+			violations = append(violations, &BudgetViolation{ns, "max_files", int64(l.MaxFiles), int64(len(assets)), largest})
+		}
+	}
+
+	if !b.BudgetLimits.IsZero() {
+		check("", b.BudgetLimits)
+	}
+
+	var names []string
+	for ns := range b.NameSpaces {
+		names = append(names, ns)
+	}
+	sort.Strings(names)
+
+	for _, ns := range names {
+		check(strings.Trim(ns, "/"), b.NameSpaces[ns])
+	}
+
+	if len(violations) > 0 {
+		return &BudgetError{pkg, violations}
+	}
+	return nil
+}
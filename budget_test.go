@@ -0,0 +1,91 @@
+package xbindata
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBudgetCheck(t *testing.T) {
+	toc := []Asset{
+		{Name: "a.txt", Size: 10},
+		{Name: "img/b.png", Size: 300},
+		{Name: "img/c.png", Size: 200},
+		{Name: "imgx/d.png", Size: 50},
+	}
+	b := &Budget{
+		BudgetLimits: BudgetLimits{MaxFiles: 3},
+		NameSpaces: map[string]BudgetLimits{
+			"/img/": {MaxFileSize: 250, MaxTotalSize: 400},
+			"imgx":  {MaxFileSize: 100},
+		},
+	}
+	err := b.Check("assets", toc)
+	be, ok := err.(*BudgetError)
+	if !ok {
+		t.Fatalf("have error %v, want *BudgetError", err)
+	}
+	var have []string
+	for _, v := range be.Violations {
+		var names []string
+		for _, a := range v.Assets {
+			names = append(names, a.Name)
+		}
+		have = append(have, v.String()+" "+strings.Join(names, ","))
+	}
+	want := []string{
+		".: max_files exceeded: 4 > 3 img/b.png,img/c.png,imgx/d.png,a.txt",
+		"img: max_file_size exceeded: 300 B > 250 B img/b.png",
+		"img: max_total_size exceeded: 500 B > 400 B img/b.png,img/c.png",
+	}
+	if strings.Join(have, "\n") != strings.Join(want, "\n") {
+		t.Errorf("have violations\n%s\nwant\n%s", strings.Join(have, "\n"), strings.Join(want, "\n"))
+	}
+	if msg := err.Error(); !strings.HasPrefix(msg, `package "assets" exceeds the size budget:`) || !strings.Contains(msg, "img/b.png  300 B") {
+		t.Errorf("bad error message %q", msg)
+	}
+
+	if err = (&Budget{BudgetLimits: BudgetLimits{MaxTotalSize: 560}}).Check("assets", toc); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestBudgetTranslate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbbudget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = os.Mkdir(filepath.Join(dir, "static"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "static", "big.bin"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	build := func(opts ...Option) (*BuildResult, error) {
+		b, err := NewBuilder(append([]Option{
+			WithDir(dir),
+			WithInput(InputConfig{Path: "static"}),
+			WithPrefix("static"),
+			WithOutputFS(NewMemOutputFS()),
+			WithBudget(&Budget{BudgetLimits: BudgetLimits{MaxFileSize: 10}}),
+		}, opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b.Build(context.Background())
+	}
+
+	if _, err = build(); err == nil {
+		t.Error("budget not checked")
+	} else if _, ok := err.(*BudgetError); !ok {
+		t.Errorf("have error %v, want *BudgetError", err)
+	}
+	if result, err := build(WithBudgetWarnOnly()); err != nil || result.Count != 1 {
+		t.Errorf("warn only: have %v, %v", result, err)
+	}
+}
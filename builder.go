@@ -0,0 +1,458 @@
+package xbindata
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/gobwas/glob"
+)
+
+// Option configures the Builder config. See NewBuilder.
+type Option func(c *Config) error
+
+// Builder builds the assets package of config, created by NewBuilder.
+type Builder struct {
+	config *Config
+}
+
+// NewBuilder returns the builder of config, starting from NewConfig and
+// configured by the options, in order. The incompatible options are
+// reported here, instead of by Build:
+//
+//	b, err := xbindata.NewBuilder(
+//		xbindata.WithPackage("assets"),
+//		xbindata.WithOutput("assets/assets.go"),
+//		xbindata.WithInput(xbindata.InputConfig{Path: "static", Recursive: true}),
+//		xbindata.WithPrefix("static"),
+//		xbindata.WithFileSystem(),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	result, err := b.Build(ctx)
+func NewBuilder(opts ...Option) (b *Builder, err error) {
+	c := NewConfig()
+	for _, opt := range opts {
+		if err = opt(c); err != nil {
+			return
+		}
+	}
+	if err = c.check(); err != nil {
+		return
+	}
+	return &Builder{c}, nil
+}
+
+// Config returns a copy of builder config.
+func (b *Builder) Config() *Config {
+	c := *b.config
+	c.Tags = append([]string(nil), c.Tags...)
+	c.Input = append([]InputConfig(nil), c.Input...)
+	return &c
+}
+
+// Build builds the assets and returns the structured result. The ctx
+// cancels the walking of inputs and the compressing of assets, returning
+// the ctx error. The builder can be built many times.
+func (b *Builder) Build(ctx context.Context) (*BuildResult, error) {
+	return translate(ctx, b.Config())
+}
+
+// check reports the incompatible options.
+func (c *Config) check() error {
+	if c.Debug && c.Dev {
+		return fmt.Errorf("the debug and dev options are exclusive")
+	}
+	if c.Outlined {
+		if c.Debug || c.Dev {
+			return fmt.Errorf("the debug and dev options aren't supported by outlined")
+		}
+	} else {
+		switch {
+		case c.Hybrid:
+			return fmt.Errorf("the hybrid option requires outlined")
+		case c.OutlinedProgram:
+			return fmt.Errorf("the outlined program option requires outlined")
+		case c.OutlinedApi != "":
+			return fmt.Errorf("the outlined api option requires outlined")
+		}
+	}
+	if c.SelfTest {
+		switch {
+		case c.Debug || c.Dev:
+			return fmt.Errorf("the self test option isn't supported by debug and dev")
+		case c.OutlinedProgram:
+			return fmt.Errorf("the self test option isn't supported by outlined program")
+		case c.OulinedSkipApi:
+			return fmt.Errorf("the self test option requires the outlined api")
+		}
+	}
+	if c.ShardSize != 0 {
+		switch {
+		case c.ShardSize < 0:
+			return fmt.Errorf("invalid shard size %d", c.ShardSize)
+		case c.Outlined:
+			return fmt.Errorf("the shard size option isn't supported by outlined")
+		case c.Debug || c.Dev:
+			return fmt.Errorf("the shard size option isn't supported by debug and dev")
+		case c.Output == OutputToStdout:
+			return fmt.Errorf("the shard size option requires the output file")
+		}
+	}
+	switch c.Accessors {
+	case "", AccessorsConst, AccessorsTree:
+	default:
+		return fmt.Errorf("invalid accessors mode %q", c.Accessors)
+	}
+	switch c.HashedNames {
+	case "", HashedNamesAdd, HashedNamesOnly:
+	default:
+		return fmt.Errorf("invalid hashed names mode %q", c.HashedNames)
+	}
+	return nil
+}
+
+// WithConfig calls f with the config. Use it to set the options without
+// builder option.
+func WithConfig(f func(c *Config)) Option {
+	return func(c *Config) error {
+		f(c)
+		return nil
+	}
+}
+
+// WithPackage sets the package name (or path, whose base is the name).
+// Defaults to `main`.
+func WithPackage(pkg string) Option {
+	return func(c *Config) error {
+		if pkg == "" {
+			return fmt.Errorf("empty package")
+		}
+		c.Package = pkg
+		return nil
+	}
+}
+
+// WithInput appends the inputs.
+func WithInput(input ...InputConfig) Option {
+	return func(c *Config) error {
+		c.Input = append(c.Input, input...)
+		return nil
+	}
+}
+
+// WithOutput sets the output file: the Go source file or, if outlined, the
+// archive file. See Config.Output.
+func WithOutput(pth string) Option {
+	return func(c *Config) error {
+		c.Output = pth
+		return nil
+	}
+}
+
+// WithOutputWriter sets the writer of outlined program contents. See
+// Config.OutputWriter.
+func WithOutputWriter(w io.Writer) Option {
+	return func(c *Config) error {
+		c.OutputWriter = w
+		return nil
+	}
+}
+
+// WithPrefix sets the path prefix stripped from asset names.
+func WithPrefix(prefix string) Option {
+	return func(c *Config) error {
+		c.Prefix = prefix
+		return nil
+	}
+}
+
+// WithTags appends the build tags of generated code.
+func WithTags(tags ...string) Option {
+	return func(c *Config) error {
+		c.Tags = append(c.Tags, tags...)
+		return nil
+	}
+}
+
+// WithIgnore appends the ignore regex patterns.
+func WithIgnore(pattern ...string) Option {
+	return func(c *Config) error {
+		for _, pattern := range pattern {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("ignore pattern %q: %v", pattern, err)
+			}
+			c.Ignore = append(c.Ignore, re)
+		}
+		return nil
+	}
+}
+
+// WithIgnoreGlob appends the ignore glob patterns.
+func WithIgnoreGlob(pattern ...string) Option {
+	return func(c *Config) error {
+		for _, pattern := range pattern {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("ignore glob pattern %q: %v", pattern, err)
+			}
+			c.IgnoreGlob = append(c.IgnoreGlob, g)
+		}
+		return nil
+	}
+}
+
+// WithNoCompress disables the GZIP compression of assets.
+func WithNoCompress() Option {
+	return func(c *Config) error {
+		c.NoCompress = true
+		return nil
+	}
+}
+
+// WithNoMemCopy enables the `.rodata` hack. See Config.NoMemCopy.
+func WithNoMemCopy() Option {
+	return func(c *Config) error {
+		c.NoMemCopy = true
+		return nil
+	}
+}
+
+// WithNoMetadata disables the size, mode and modification time of assets.
+func WithNoMetadata() Option {
+	return func(c *Config) error {
+		c.NoMetadata = true
+		return nil
+	}
+}
+
+// WithMode overrides the file mode of assets.
+func WithMode(mode uint) Option {
+	return func(c *Config) error {
+		c.Mode = mode
+		return nil
+	}
+}
+
+// WithModTime overrides the modification time of assets.
+func WithModTime(t time.Time) Option {
+	return func(c *Config) error {
+		c.ModTime = t.Unix()
+		return nil
+	}
+}
+
+// WithDebug enables the debug build. See Config.Debug.
+func WithDebug() Option {
+	return func(c *Config) error {
+		c.Debug = true
+		return nil
+	}
+}
+
+// WithDev enables the dev build. See Config.Dev.
+func WithDev() Option {
+	return func(c *Config) error {
+		c.Dev = true
+		return nil
+	}
+}
+
+// WithFileSystem generates the file system API, with the load callbacks
+// (`IMPORT_PATH.FUNC` names).
+func WithFileSystem(loadCallbacks ...string) Option {
+	return func(c *Config) error {
+		c.FileSystem = true
+		c.FileSystemLoadCallbacks = append(c.FileSystemLoadCallbacks, loadCallbacks...)
+		return nil
+	}
+}
+
+// WithNoAutoLoad disables the loading of assets on package init.
+func WithNoAutoLoad() Option {
+	return func(c *Config) error {
+		c.NoAutoLoad = true
+		return nil
+	}
+}
+
+// WithOutlined stores the assets into the outlined archive (see
+// WithOutput), with the Go API source file api.
+func WithOutlined(api string) Option {
+	return func(c *Config) error {
+		c.Outlined = true
+		c.OutlinedApi = api
+		if c.Output == DefaultOutput {
+			c.Output = ""
+		}
+		return nil
+	}
+}
+
+// WithOutlinedProgram appends the outlined archive to the program file.
+// Requires WithOutlined.
+func WithOutlinedProgram() Option {
+	return func(c *Config) error {
+		c.OutlinedProgram = true
+		return nil
+	}
+}
+
+// WithHybrid generates the dev mode local file system too, enabled by the
+// `dev` build tag. Implies WithFileSystem and requires WithOutlined.
+func WithHybrid() Option {
+	return func(c *Config) error {
+		c.Hybrid = true
+		c.FileSystem = true
+		return nil
+	}
+}
+
+// WithProduction walks the inputs in production mode.
+func WithProduction() Option {
+	return func(c *Config) error {
+		c.InputProduction = true
+		return nil
+	}
+}
+
+// WithProfile sets the config profile passed to the inputs.
+func WithProfile(profile string) Option {
+	return func(c *Config) error {
+		c.Profile = profile
+		return nil
+	}
+}
+
+// WithBudget sets the size limits of assets.
+func WithBudget(budget *Budget) Option {
+	return func(c *Config) error {
+		c.Budget = budget
+		return nil
+	}
+}
+
+// WithBudgetWarnOnly logs the budget violations instead of fail.
+func WithBudgetWarnOnly() Option {
+	return func(c *Config) error {
+		c.BudgetWarnOnly = true
+		return nil
+	}
+}
+
+// WithIntegritySHA384 adds the SHA-384 digest to the Subresource Integrity
+// values.
+func WithIntegritySHA384() Option {
+	return func(c *Config) error {
+		c.IntegritySHA384 = true
+		return nil
+	}
+}
+
+// WithChecksums writes the `SHA256SUMS` files.
+func WithChecksums() Option {
+	return func(c *Config) error {
+		c.Checksums = true
+		return nil
+	}
+}
+
+// WithShardSize splits the embedded assets data into packages of about size
+// bytes. See Config.ShardSize.
+func WithShardSize(size int64) Option {
+	return func(c *Config) error {
+		c.ShardSize = size
+		return nil
+	}
+}
+
+// WithSelfTest writes the test of generated package. See Config.SelfTest.
+func WithSelfTest() Option {
+	return func(c *Config) error {
+		c.SelfTest = true
+		return nil
+	}
+}
+
+// WithMetadata appends the asset metadata rules.
+func WithMetadata(rule ...MetadataRule) Option {
+	return func(c *Config) error {
+		c.Metadata = append(c.Metadata, rule...)
+		return nil
+	}
+}
+
+// WithMetadataSidecars reads the asset metadata from sidecar files.
+func WithMetadataSidecars() Option {
+	return func(c *Config) error {
+		c.MetadataSidecars = true
+		return nil
+	}
+}
+
+// WithContentTypes sets the `content-type` metadata of assets.
+func WithContentTypes() Option {
+	return func(c *Config) error {
+		c.ContentTypes = true
+		return nil
+	}
+}
+
+// WithPreserveDirs stores the directory entries.
+func WithPreserveDirs() Option {
+	return func(c *Config) error {
+		c.PreserveDirs = true
+		return nil
+	}
+}
+
+// WithCollisions sets the asset name collisions detection.
+func WithCollisions(collisions Collisions) Option {
+	return func(c *Config) error {
+		c.Collisions = collisions
+		return nil
+	}
+}
+
+// WithAccessors generates the typed asset accessors: AccessorsConst or
+// AccessorsTree.
+func WithAccessors(mode string) Option {
+	return func(c *Config) error {
+		c.Accessors = mode
+		return nil
+	}
+}
+
+// WithHashedNames publishes the assets under the fingerprinted names:
+// HashedNamesAdd or HashedNamesOnly. The zero length and empty manifest
+// are the defaults.
+func WithHashedNames(mode string, length int, manifest string) Option {
+	return func(c *Config) error {
+		c.HashedNames = mode
+		c.HashedNamesLength = length
+		c.HashedNamesManifest = manifest
+		return nil
+	}
+}
+
+// WithOutputFS writes the generated files into fs, instead of OS file
+// system. See NewMemOutputFS.
+func WithOutputFS(fs OutputFS) Option {
+	return func(c *Config) error {
+		c.OutputFS = fs
+		return nil
+	}
+}
+
+// WithDir sets the directory of relative input and output paths, instead of
+// the working directory.
+func WithDir(dir string) Option {
+	return func(c *Config) error {
+		c.Dir = dir
+		return nil
+	}
+}
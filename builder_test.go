@@ -0,0 +1,93 @@
+package xbindata
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbbuilder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for name, data := range map[string]string{"static/a.txt": "aaa", "static/b.tmp": "bbb", "static/sub/c.txt": "ccc"} {
+		pth := filepath.Join(dir, name)
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fs := NewMemOutputFS()
+	b, err := NewBuilder(
+		WithDir(dir),
+		WithPackage("assets"),
+		WithOutput("assets/assets.go"),
+		WithInput(InputConfig{Path: "static", Recursive: true}),
+		WithPrefix("static"),
+		WithIgnore(`\.tmp$`),
+		WithNoCompress(),
+		WithOutputFS(fs),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := b.Config(); c.Package != "assets" || !c.NoCompress || len(c.Input) != 1 || len(c.Ignore) != 1 {
+		t.Errorf("options not applied: %+v", c)
+	}
+
+	result, err := b.Build(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Package != "assets" || result.Count != 2 || result.Assets[0].Name != "a.txt" || result.Assets[1].Name != "sub/c.txt" {
+		t.Errorf("bad result %+v", result)
+	}
+	if data, err := fs.ReadFile(filepath.Join(dir, "assets", "assets.go")); err != nil || !strings.Contains(string(data), "package assets\n") {
+		t.Errorf("bad output: %v", err)
+	}
+
+	// the builder config is not modified by the build
+	if _, err = b.Build(context.Background()); err != nil {
+		t.Errorf("rebuild: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if result, err = b.Build(ctx); err != context.Canceled || result != nil {
+		t.Errorf("have %v, %v, want canceled error", result, err)
+	}
+}
+
+func TestBuilderOptionErrors(t *testing.T) {
+	for _, tt := range []struct {
+		opts []Option
+		err  string
+	}{
+		{[]Option{WithIgnore("(")}, `ignore pattern "("`},
+		{[]Option{WithIgnoreGlob("[")}, `ignore glob pattern "["`},
+		{[]Option{WithDebug(), WithDev()}, "the debug and dev options are exclusive"},
+		{[]Option{WithOutlined("api.go"), WithDev()}, "aren't supported by outlined"},
+		{[]Option{WithHybrid()}, "the hybrid option requires outlined"},
+		{[]Option{WithOutlinedProgram()}, "the outlined program option requires outlined"},
+		{[]Option{WithSelfTest(), WithDebug()}, "the self test option isn't supported by debug and dev"},
+		{[]Option{WithOutlined("api.go"), WithOutlinedProgram(), WithSelfTest()}, "isn't supported by outlined program"},
+		{[]Option{WithShardSize(-1)}, "invalid shard size -1"},
+		{[]Option{WithOutlined("api.go"), WithShardSize(1)}, "the shard size option isn't supported by outlined"},
+		{[]Option{WithDev(), WithShardSize(1)}, "the shard size option isn't supported by debug and dev"},
+		{[]Option{WithOutput(OutputToStdout), WithShardSize(1)}, "the shard size option requires the output file"},
+		{[]Option{WithAccessors("bad")}, `invalid accessors mode "bad"`},
+		{[]Option{WithHashedNames("bad", 0, "")}, `invalid hashed names mode "bad"`},
+	} {
+		if b, err := NewBuilder(tt.opts...); err == nil || !strings.Contains(err.Error(), tt.err) || b != nil {
+			t.Errorf("have error %v, want %q", err, tt.err)
+		}
+	}
+}
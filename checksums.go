@@ -0,0 +1,85 @@
+package xbindata
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+const checksumsFileName = "SHA256SUMS"
+
+// writeTreeChecksums writes the `<api base>_SHA256SUMS` file with the digest
+// of each asset. Run `sha256sum -c` into the restored assets directory to
+// verify it.
+func writeTreeChecksums(c *Config, toc []Asset) (pth string, err error) {
+	var (
+		api     = c.apiOutput()
+		digests = make(map[string][sha256.Size]byte, len(toc))
+		buf     bytes.Buffer
+	)
+
+	for i := range toc {
+		if toc[i].link != "" {
+			// sha256sum follows the symlinks
+			continue
+		}
+		var d *[sha256.Size]byte
+		if d, err = toc[i].Digest(); err != nil {
+			return
+		}
+		digests[toc[i].Name] = *d
+	}
+
+	if err = xbcommon.WriteSHA256Sums(&buf, digests); err != nil {
+		return
+	}
+
+	pth = filepath.Join(filepath.Dir(api), strings.TrimSuffix(filepath.Base(api), ".go")+"_"+checksumsFileName)
+	err = c.writeFile(pth, buf.Bytes())
+	return
+}
+
+// writeArchiveChecksum sets the archive digest into the `SHA256SUMS` file of
+// archive directory, keeping the digests of other archives.
+func writeArchiveChecksum(c *Config, archive string, d [sha256.Size]byte) (pth string, err error) {
+	var (
+		dir     = filepath.Dir(archive)
+		digests = map[string][sha256.Size]byte{}
+		data    []byte
+		buf     bytes.Buffer
+	)
+
+	pth = filepath.Join(dir, checksumsFileName)
+
+	if data, err = c.readFile(pth); err == nil {
+		s := bufio.NewScanner(bytes.NewReader(data))
+		for s.Scan() {
+			// format: `HEX  NAME`
+			parts := strings.SplitN(s.Text(), "  ", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			if b, err := hex.DecodeString(parts[0]); err == nil && len(b) == sha256.Size {
+				var d [sha256.Size]byte
+				copy(d[:], b)
+				digests[parts[1]] = d
+			}
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	digests[filepath.Base(archive)] = d
+
+	if err = xbcommon.WriteSHA256Sums(&buf, digests); err != nil {
+		return
+	}
+	err = c.writeFile(pth, buf.Bytes())
+	return
+}
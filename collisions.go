@@ -0,0 +1,125 @@
+package xbindata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	CollisionExact   = "exact"
+	CollisionCase    = "case"
+	CollisionUnicode = "unicode"
+)
+
+// Collisions defines the asset name collisions detection.
+type Collisions struct {
+	// Strict fails the build when two inputs maps to the same asset name,
+	// except if only one of then is an override input (see
+	// InputConfig.Override). Two override inputs collides too.
+	Strict bool
+	// CaseFold fails the build on names that differs only by case. This
+	// names breaks the extraction on case-insensitive file systems.
+	CaseFold bool `mapstructure:"case_fold" yaml:"case_fold"`
+	// Unicode fails the build on names that are equal after the Unicode NFC
+	// normalization.
+	Unicode bool
+}
+
+// Collision holds the conflicting names and source paths.
+type Collision struct {
+	Kind  string
+	Names []string
+	Paths []string
+}
+
+func (c *Collision) String() string {
+	var lines []string
+	for i, pth := range c.Paths {
+		lines = append(lines, fmt.Sprintf("  - %q from %s", c.Names[i], pth))
+	}
+	return fmt.Sprintf("%s collision of %q:\n%s", c.Kind, c.Names[0], strings.Join(lines, "\n"))
+}
+
+// CollisionError is returned by Translate when name collisions was
+// detected.
+type CollisionError struct {
+	Collisions []*Collision
+}
+
+func (e *CollisionError) Error() string {
+	var s = make([]string, len(e.Collisions))
+	for i, c := range e.Collisions {
+		s[i] = c.String()
+	}
+	return fmt.Sprintf("asset name collisions:\n%s", strings.Join(s, "\n"))
+}
+
+// foldedCollisions returns the collisions of names that are equal after
+// the fold function.
+func foldedCollisions(kind string, toc []Asset, fold func(name string) string) (collisions []*Collision) {
+	var (
+		groups = map[string][]*Asset{}
+		keys   []string
+	)
+	for i := range toc {
+		key := fold(toc[i].Name)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], &toc[i])
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if group := groups[key]; len(group) > 1 {
+			c := &Collision{Kind: kind}
+			for _, a := range group {
+				c.Names = append(c.Names, a.Name)
+				c.Paths = append(c.Paths, a.Path)
+			}
+			collisions = append(collisions, c)
+		}
+	}
+	return
+}
+
+// Check checks the exact collisions registered by toc and the case-fold
+// and Unicode normalization collisions of toc assets.
+func (c Collisions) Check(tocr *tocRegister) error {
+	var collisions []*Collision
+
+	if c.Strict && len(tocr.collisions) > 0 {
+		var names []string
+		for name := range tocr.collisions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			paths := tocr.collisions[name]
+			collisions = append(collisions, &Collision{CollisionExact, repeatString(name, len(paths)), paths})
+		}
+	}
+
+	if c.CaseFold {
+		collisions = append(collisions, foldedCollisions(CollisionCase, tocr.toc, strings.ToLower)...)
+	}
+
+	if c.Unicode {
+		collisions = append(collisions, foldedCollisions(CollisionUnicode, tocr.toc, norm.NFC.String)...)
+	}
+
+	if len(collisions) > 0 {
+		return &CollisionError{collisions}
+	}
+	return nil
+}
+
+func repeatString(s string, count int) (r []string) {
+	r = make([]string, count)
+	for i := range r {
+		r[i] = s
+	}
+	return
+}
@@ -0,0 +1,92 @@
+package xbindata
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCollisions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbcollisions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var (
+		// é composed and decomposed
+		nfc = "d/caf\u00e9.txt"
+		nfd = "e/cafe\u0301.txt"
+	)
+	for _, name := range []string{"a/x.txt", "b/x.txt", "c/X.txt", nfc, nfd} {
+		pth := filepath.Join(dir, name)
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	input := func(name string, override bool) InputConfig {
+		return InputConfig{Path: name, Prefix: name, Override: override}
+	}
+
+	for _, tt := range []struct {
+		name       string
+		collisions Collisions
+		inputs     []InputConfig
+		err        []string
+	}{
+		{"loose", Collisions{}, []InputConfig{input("a", false), input("b", false)}, nil},
+		{"strict", Collisions{Strict: true}, []InputConfig{input("a", false), input("b", false)}, []string{
+			`exact collision of "x.txt":`,
+			`"x.txt" from ` + filepath.Join(dir, "a", "x.txt"),
+			`"x.txt" from ` + filepath.Join(dir, "b", "x.txt"),
+		}},
+		{"override", Collisions{Strict: true}, []InputConfig{input("a", false), input("b", true)}, nil},
+		{"overrides", Collisions{Strict: true}, []InputConfig{input("a", true), input("b", true)}, []string{
+			`exact collision of "x.txt":`,
+			`"x.txt" from ` + filepath.Join(dir, "a", "x.txt"),
+			`"x.txt" from ` + filepath.Join(dir, "b", "x.txt"),
+		}},
+		{"loose overrides", Collisions{}, []InputConfig{input("a", true), input("b", true)}, nil},
+		{"case", Collisions{CaseFold: true}, []InputConfig{input("a", false), input("c", false)}, []string{
+			`case collision of "x.txt":`,
+			`"X.txt" from ` + filepath.Join(dir, "c", "X.txt"),
+		}},
+		{"no case", Collisions{Strict: true}, []InputConfig{input("a", false), input("c", false)}, nil},
+		{"unicode", Collisions{Unicode: true}, []InputConfig{input("d", false), input("e", false)}, []string{
+			fmt.Sprintf("unicode collision of %q:", nfc[2:]),
+			fmt.Sprintf("%q from %s", nfd[2:], filepath.Join(dir, nfd)),
+		}},
+	} {
+		b, err := NewBuilder(
+			WithDir(dir),
+			WithInput(tt.inputs...),
+			WithCollisions(tt.collisions),
+			WithOutputFS(NewMemOutputFS()),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = b.Build(context.Background())
+		if tt.err == nil {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			}
+			continue
+		}
+		if _, ok := err.(*CollisionError); !ok {
+			t.Errorf("%s: have error %v, want *CollisionError", tt.name, err)
+			continue
+		}
+		for _, s := range tt.err {
+			if !strings.Contains(err.Error(), s) {
+				t.Errorf("%s: %q not found in %q", tt.name, s, err)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package xbindata
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -18,12 +19,24 @@ import (
 
 const DefaultOutput = "assets.go"
 
+const (
+	HashedNamesAdd  = "add"
+	HashedNamesOnly = "only"
+
+	DefaultHashedNamesManifest = "manifest.json"
+)
+
 // InputConfig defines options on a asset directory to be convert.
 type InputConfig struct {
 	// Path defines a directory containing asset files to be included
-	// in the generated output.
+	// in the generated output. Accepts `.zip`, `.tar`, `.tar.gz` and
+	// `.tgz` archives too, walked as if it were a directory.
 	Path string
 
+	// Subdir is the archive directory to be walked, if Path is a archive.
+	// Is invalid for the other inputs.
+	Subdir string
+
 	// Recusive defines whether subdirectories of Path
 	// should be recursively included in the conversion.
 	Recursive bool
@@ -49,7 +62,27 @@ type InputConfig struct {
 
 	DirReplacesCount int
 
+	// Override marks the input assets as winners on name collisions with
+	// assets of other inputs. Use it when the overriding is intentional.
+	Override bool
+
+	// Symlinks is the symlinks policy: `follow` (the default), `preserve`,
+	// `skip` or `error`. In `preserve` policy, the symlinks are stored as
+	// symlink entries and the targets must be inside of the assets root.
+	Symlinks string
+
 	WalkFunc func(visited *map[string]bool, prod, recursive bool, cb func(info walker.FileInfo) error) error
+
+	// Walker is the name of in-process input walker. See RegisterWalker.
+	Walker string
+
+	// Profile is the selected config profile, visible to the walkers. The
+	// `.xbwalk` programs receives it in the XB_PROFILE environment variable.
+	// Defaults to Config.Profile.
+	Profile string
+
+	// dirs walks the directories too. See Config.PreserveDirs.
+	dirs bool
 }
 
 func (i InputConfig) Walk(visited *map[string]bool, prod bool, cb walker.WalkCallback) (err error) {
@@ -57,12 +90,26 @@ func (i InputConfig) Walk(visited *map[string]bool, prod bool, cb walker.WalkCal
 		return i.WalkFunc(visited, prod, i.Recursive, i.prepareCb(cb))
 	}
 
+	if i.Walker != "" {
+		w, ok := GetWalker(i.Walker)
+		if !ok {
+			return fmt.Errorf("walker %q is not registered", i.Walker)
+		}
+		cb = i.prepareCb(cb)
+		return w(&i, prod, func(entry walker.Entry) error {
+			return walkEntry(i.Path, entry, cb)
+		})
+	}
+
 	return i.DefaultWalk(visited, i.Recursive, cb)
 }
 
 func (i InputConfig) DefaultWalk(visited *map[string]bool, recursive bool, cb walker.WalkCallback) (err error) {
+	if IsArchiveInput(i.Path) {
+		return i.walkArchive(visited, recursive, cb)
+	}
 	var pth = i.Path
-	w := walker.Walker{Recursive: recursive, VisitedPaths: visited}
+	w := walker.Walker{Recursive: recursive, VisitedPaths: visited, Symlinks: i.Symlinks, Dirs: i.dirs}
 	return w.Walk(pth, i.prepareCb(cb))
 }
 
@@ -99,6 +146,14 @@ type Config struct {
 	// OutputWriter defines the output writer for the generated code.
 	OutputWriter io.Writer
 
+	// OutputFS is the file system of all generated files. Defaults to
+	// OSOutputFS. Use a MemOutputFS to generate into memory.
+	OutputFS OutputFS
+
+	// Dir is the base directory of relative input, prefix and output
+	// paths. Defaults to the working directory.
+	Dir string
+
 	// Prefix defines a path prefix which should be stripped from all
 	// file names when generating the keys in the table of contents.
 	// For example, running without the `-prefix` flag, we get:
@@ -245,7 +300,91 @@ type Config struct {
 
 	InputProduction bool
 
+	// Profile is the selected config profile, passed to the inputs. See
+	// InputConfig.Profile.
+	Profile string
+
 	FileSystemLoadCallbacks []string
+
+	// Budget defines the size limits of the assets. When exceeded,
+	// Translate fails with a *BudgetError.
+	Budget *Budget
+
+	// BudgetWarnOnly logs the budget violations instead of fail.
+	BudgetWarnOnly bool
+
+	// IntegritySHA384 adds the SHA-384 digest to the Subresource Integrity
+	// value of embedded assets.
+	IntegritySHA384 bool
+
+	// Checksums writes the `SHA256SUMS` files, accepted by `sha256sum -c`,
+	// of assets (for the restored trees) and of the outlined archive.
+	Checksums bool
+
+	// SelfTest writes the `<api base>_xb_test.go` test of generated
+	// package, next to the Go API file. The test opens each asset by the
+	// package API, checks the size, SHA-256 digest and the assets tree and
+	// runs the testing/fstest.TestFS.
+	SelfTest bool
+
+	// ShardSize splits the embedded assets data into the
+	// `<output base>_shard_NNN` packages of about ShardSize bytes of Go
+	// source, compiled apart, cutting the compiler memory. The output file
+	// keeps the API, the table of contents and the tree. Zero disables the
+	// sharding.
+	ShardSize int64
+
+	// Metadata are the asset metadata rules, applied in order.
+	Metadata []MetadataRule
+
+	// MetadataSidecars reads the asset metadata from the `NAME.meta.yaml`
+	// sidecar file of asset. The sidecar files are not included as assets.
+	MetadataSidecars bool
+
+	// ContentTypes sets the `content-type` metadata of assets, from the
+	// file extension or detected from the contents.
+	ContentTypes bool
+
+	// PreserveDirs stores the directories, including the empty
+	// directories, with mode and modification time. Archives with
+	// directory entries requires a runtime with directory entries support.
+	PreserveDirs bool
+
+	// Collisions defines the asset name collisions detection. By default,
+	// the asset of the last input wins.
+	Collisions Collisions
+
+	// Accessors generates typed asset name accessors, so a missing asset
+	// becomes a compile error. Accepts AccessorsConst (a constant for each
+	// asset and directory name) or AccessorsTree (a nested struct tree,
+	// example: `assets.Static.CSS.AppCSS`).
+	Accessors string
+
+	// HashedNames publishes each asset under the fingerprinted name
+	// `name.<shorthash>.ext`. Accepts HashedNamesAdd (publish under original
+	// and hashed names) or HashedNamesOnly (publish under hashed name only).
+	//
+	// The generated code exposes the `HashedName(name) string` and
+	// `Original(hashed) string` functions.
+	HashedNames string
+
+	// HashedNamesLength is the count of hex digest chars of hashed names.
+	// Defaults to xbcommon.DefaultHashedNameLength.
+	HashedNamesLength int
+
+	// HashedNamesManifest is the name of the webpack-style manifest asset,
+	// mapping original names to hashed names. Defaults to `manifest.json`.
+	HashedNamesManifest string
+
+	// ctx is the context of build. See Builder.Build.
+	ctx context.Context
+}
+
+func (c *Config) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
 }
 
 // NewConfig returns a default configuration struct.
@@ -266,11 +405,32 @@ func (c *Config) validate() error {
 		return fmt.Errorf("Missing package name")
 	}
 
+	if c.Dir != "" {
+		var err error
+		if c.Dir, err = filepath.Abs(c.Dir); err != nil {
+			return err
+		}
+		for i := range c.Input {
+			c.Input[i].Path = c.outputPath(c.Input[i].Path)
+		}
+	}
+
 	for _, input := range c.Input {
 		_, err := os.Lstat(input.Path)
 		if err != nil {
 			return fmt.Errorf("Failed to stat input path '%s': %v", input.Path, err)
 		}
+		if input.Walker != "" {
+			if _, ok := GetWalker(input.Walker); !ok {
+				return fmt.Errorf("Unknown walker %q of input path '%s'", input.Walker, input.Path)
+			}
+		}
+		if !walker.ValidSymlinks(input.Symlinks) {
+			return fmt.Errorf("Invalid symlinks policy %q of input path '%s'", input.Symlinks, input.Path)
+		}
+		if input.Subdir != "" && !IsArchiveInput(input.Path) {
+			return fmt.Errorf("Subdir %q of input path '%s' requires an archive", input.Subdir, input.Path)
+		}
 	}
 
 	if c.Outlined {
@@ -290,30 +450,13 @@ func (c *Config) validate() error {
 			}
 		}
 	} else if c.Output == "" {
-		cwd, err := os.Getwd()
-		if err != nil {
-			return fmt.Errorf("Unable to determine current working directory.")
-		}
-
-		c.Output = filepath.Join(cwd, "xb.go")
+		c.Output = "xb.go"
 	}
 
-	stat, err := os.Lstat(c.Output)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return fmt.Errorf("Output path: %v", err)
-		}
-
-		// File does not exist. This is fine, just make
-		// sure the directory it is to be in exists.
-		dir, _ := filepath.Split(c.Output)
-		if dir != "" {
-			err = os.MkdirAll(dir, 0744)
-
-			if err != nil {
-				return fmt.Errorf("Create output directory: %v", err)
-			}
-		}
+	// The output directories are created on write.
+	stat, err := c.statFile(c.Output)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Output path: %v", err)
 	}
 
 	if stat != nil && stat.IsDir() {
@@ -324,5 +467,27 @@ func (c *Config) validate() error {
 		c.FileSystem = true
 	}
 
+	for i := range c.Metadata {
+		if err := c.Metadata[i].compile(); err != nil {
+			return err
+		}
+	}
+
+	switch c.Accessors {
+	case "", AccessorsConst, AccessorsTree:
+	default:
+		return fmt.Errorf("Invalid accessors mode %q.", c.Accessors)
+	}
+
+	switch c.HashedNames {
+	case "":
+	case HashedNamesAdd, HashedNamesOnly:
+		if c.HashedNamesManifest == "" {
+			c.HashedNamesManifest = DefaultHashedNamesManifest
+		}
+	default:
+		return fmt.Errorf("Invalid hashed names mode %q.", c.HashedNames)
+	}
+
 	return nil
 }
@@ -0,0 +1,243 @@
+package xbindata
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// ConfigError is a config file error, with the position of the bad key or
+// value.
+type ConfigError struct {
+	File   string
+	Line   int
+	Column int
+	// Key is the dotted path of the key, example: `outlined.0.inputs.1.ns`.
+	Key string
+	Msg string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Msg)
+}
+
+// ConfigErrors is a list of config file errors.
+type ConfigErrors []*ConfigError
+
+func (errs ConfigErrors) Error() string {
+	var s = make([]string, len(errs))
+	for i, err := range errs {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "\n")
+}
+
+// IsStrictConfigFile returns if the config file format is checked by
+// CheckConfigFile: YAML or JSON.
+func IsStrictConfigFile(pth string) bool {
+	switch strings.ToLower(filepath.Ext(pth)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// CheckConfigFile checks the config file. See CheckConfig.
+func CheckConfigFile(pth string) (err error) {
+	var data []byte
+	if data, err = ioutil.ReadFile(pth); err != nil {
+		return
+	}
+	return CheckConfig(pth, data)
+}
+
+// CheckConfig checks the YAML (or JSON) config data, in file, against the
+// ManyConfig keys and value types. Unlike the mapstructure decoder, the
+// unknown keys are errors. Returns ConfigErrors, with the position of each
+// error.
+func CheckConfig(file string, data []byte) error {
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	c := configChecker{file: file}
+	c.check(doc.Content[0], reflect.TypeOf(ManyConfig{}), "")
+	if len(c.errs) > 0 {
+		return c.errs
+	}
+	return nil
+}
+
+type configChecker struct {
+	file string
+	errs ConfigErrors
+}
+
+func (c *configChecker) errorf(n *yaml3.Node, key, format string, args ...interface{}) {
+	c.errs = append(c.errs, &ConfigError{c.file, n.Line, n.Column, key, fmt.Sprintf(format, args...)})
+}
+
+func (c *configChecker) check(n *yaml3.Node, t reflect.Type, key string) {
+	for n.Kind == yaml3.AliasNode {
+		n = n.Alias
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if n.Kind == yaml3.ScalarNode && n.Tag == "!!null" {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml3.MappingNode {
+			c.errorf(n, key, "%s: expected mapping", configKeyName(key))
+			return
+		}
+		fields := configFields(t)
+		c.eachPair(n, func(k, v *yaml3.Node) {
+			if k.Value == "<<" {
+				c.check(v, t, key)
+				return
+			}
+			field, ok := fields[strings.ToLower(k.Value)]
+			if !ok {
+				msg := fmt.Sprintf("unknown key %q", k.Value)
+				if key != "" {
+					msg += " in " + key
+				}
+				if s := configSuggest(strings.ToLower(k.Value), fields); s != "" {
+					msg += fmt.Sprintf(", did you mean %q?", s)
+				}
+				c.errorf(k, key, "%s", msg)
+				return
+			}
+			c.check(v, field.Type, configKeyJoin(key, field.Key))
+		})
+	case reflect.Map:
+		if n.Kind != yaml3.MappingNode {
+			c.errorf(n, key, "%s: expected mapping", configKeyName(key))
+			return
+		}
+		c.eachPair(n, func(k, v *yaml3.Node) {
+			c.check(v, t.Elem(), configKeyJoin(key, k.Value))
+		})
+	case reflect.Slice, reflect.Array:
+		if n.Kind == yaml3.ScalarNode && t.Implements(configScalarSliceType) {
+			c.check(n, t.Elem(), key)
+			return
+		}
+		if n.Kind != yaml3.SequenceNode {
+			c.errorf(n, key, "%s: expected sequence", configKeyName(key))
+			return
+		}
+		for i, item := range n.Content {
+			c.check(item, t.Elem(), configKeyJoin(key, fmt.Sprint(i)))
+		}
+	default:
+		if n.Kind != yaml3.ScalarNode {
+			c.errorf(n, key, "%s: expected %s value", configKeyName(key), t.Kind())
+			return
+		}
+		switch t.Kind() {
+		case reflect.Bool:
+			if n.Tag != "!!bool" && !configYaml11Bool(n.Value) {
+				c.errorf(n, key, "%s: expected boolean value, got %q", configKeyName(key), n.Value)
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n.Tag != "!!int" {
+				c.errorf(n, key, "%s: expected integer value, got %q", configKeyName(key), n.Value)
+			} else if t.Kind() >= reflect.Uint && strings.HasPrefix(n.Value, "-") {
+				c.errorf(n, key, "%s: expected unsigned integer value, got %q", configKeyName(key), n.Value)
+			}
+		}
+	}
+}
+
+func (c *configChecker) eachPair(n *yaml3.Node, cb func(k, v *yaml3.Node)) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		cb(n.Content[i], n.Content[i+1])
+	}
+}
+
+// configYaml11Bool returns if value is a YAML 1.1 boolean, accepted by the
+// config loader.
+func configYaml11Bool(value string) bool {
+	switch strings.ToLower(value) {
+	case "y", "yes", "n", "no", "on", "off":
+		return true
+	}
+	return false
+}
+
+func configKeyJoin(key, sub string) string {
+	if key == "" {
+		return sub
+	}
+	return key + "." + sub
+}
+
+func configKeyName(key string) string {
+	if key == "" {
+		return "config"
+	}
+	return key
+}
+
+// configSuggest returns the most similar known key of key, if any.
+func configSuggest(key string, fields map[string]configField) (suggest string) {
+	var (
+		keys = make([]string, 0, len(fields))
+		best = len(key)/3 + 1
+	)
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if d := levenshtein(key, k); d < best {
+			best, suggest = d, fields[k].Key
+		}
+	}
+	return
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,60 @@
+package xbindata
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckConfig(t *testing.T) {
+	data := `
+embeded:
+  - pkg: assets
+outlined:
+  - pkg: assets
+    no_compres: true
+    mode: -1
+    fs: maybe
+    inputs:
+      - path: static
+        recursiv: yes
+        ignore_glob: ["*.map"]
+      - path: [a]
+    profiles:
+      prod:
+        no_compress: on
+        outpt: x.go
+extends: base.yaml
+include: [a.yaml, b.yaml]
+`
+	err := CheckConfig("xb.yaml", []byte(data))
+	errs, ok := err.(ConfigErrors)
+	if !ok {
+		t.Fatalf("have error %v, want ConfigErrors", err)
+	}
+	want := []string{
+		`xb.yaml:2:1: unknown key "embeded", did you mean "embedded"?`,
+		`xb.yaml:6:5: unknown key "no_compres" in outlined.0, did you mean "no_compress"?`,
+		`xb.yaml:7:11: outlined.0.mode: expected unsigned integer value, got "-1"`,
+		`xb.yaml:8:9: outlined.0.fs: expected boolean value, got "maybe"`,
+		`xb.yaml:11:9: unknown key "recursiv" in outlined.0.inputs.0, did you mean "recursive"?`,
+		`xb.yaml:13:15: outlined.0.inputs.1.path: expected string value`,
+		`xb.yaml:17:9: unknown key "outpt" in outlined.0.profiles.prod, did you mean "output"?`,
+	}
+	var have []string
+	for _, err := range errs {
+		have = append(have, err.Error())
+	}
+	if strings.Join(have, "\n") != strings.Join(want, "\n") {
+		t.Errorf("have errors\n%s\nwant\n%s", strings.Join(have, "\n"), strings.Join(want, "\n"))
+	}
+	if errs[1].Key != "outlined.0" {
+		t.Errorf("bad error key %q", errs[1].Key)
+	}
+
+	if err = CheckConfig("xb.yaml", []byte("embedded:\n  - pkg: assets\n    zzzzzzzz: 1\n")); err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("bad error %v, want without suggestion", err)
+	}
+	if err = CheckConfig("xb.yaml", []byte("")); err != nil {
+		t.Errorf("empty config: %v", err)
+	}
+}
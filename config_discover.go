@@ -0,0 +1,169 @@
+package xbindata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigFileName is the config file name, discovered by FindConfigFiles.
+const ConfigFileName = ".xb.yaml"
+
+// FindConfigFiles returns the ConfigFileName files into root directory and
+// its sub directories. The hidden directories, and the `vendor`,
+// `node_modules` and `testdata` directories are skipped.
+func FindConfigFiles(root string) (files []string, err error) {
+	err = filepath.Walk(root, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if pth != root && skipConfigDir(info.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == ConfigFileName {
+			files = append(files, pth)
+		}
+		return nil
+	})
+	return
+}
+
+// configFileDeps holds the input and output paths of config file.
+type configFileDeps struct {
+	file    string
+	inputs  []string
+	outputs []string
+}
+
+// ConfigFilesBuildOrder sorts the config files in dependency order: the file
+// whose inputs are the outputs (or are into the output package directories)
+// of other file, is built after it. Files without dependency keep the
+// relative order.
+func ConfigFilesBuildOrder(files []string) (sorted []string, err error) {
+	var deps = make([]configFileDeps, len(files))
+	for i, file := range files {
+		if deps[i], err = loadConfigFileDeps(file); err != nil {
+			return
+		}
+	}
+
+	var (
+		visiting = map[int]bool{}
+		done     = map[int]bool{}
+		visit    func(i int, stack []string) error
+	)
+
+	visit = func(i int, stack []string) error {
+		if done[i] {
+			return nil
+		}
+		stack = append(stack, deps[i].file)
+		if visiting[i] {
+			return fmt.Errorf("config files dependency cycle: %s", strings.Join(stack, " -> "))
+		}
+		visiting[i] = true
+		for j := range deps {
+			if j != i && deps[i].dependsOn(deps[j]) {
+				if err := visit(j, stack); err != nil {
+					return err
+				}
+			}
+		}
+		done[i] = true
+		sorted = append(sorted, deps[i].file)
+		return nil
+	}
+
+	for i := range deps {
+		if err = visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (d configFileDeps) dependsOn(other configFileDeps) bool {
+	for _, input := range d.inputs {
+		for _, output := range other.outputs {
+			if pathContains(output, input) || pathContains(input, output) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathContains returns if pth is dir or is into dir.
+func pathContains(dir, pth string) bool {
+	return pth == dir || strings.HasPrefix(pth, dir+string(filepath.Separator))
+}
+
+func loadConfigFileDeps(file string) (deps configFileDeps, err error) {
+	var (
+		m   map[string]interface{}
+		dir = filepath.Dir(file)
+	)
+	if m, err = LoadConfigFile(file); err != nil {
+		return
+	}
+
+	deps.file = file
+	abs := func(pth string) string {
+		if filepath.IsAbs(pth) {
+			return filepath.Clean(pth)
+		}
+		return filepath.Join(dir, filepath.FromSlash(pth))
+	}
+	str := func(m map[string]interface{}, key string) string {
+		s, _ := m[key].(string)
+		return s
+	}
+
+	for _, key := range []string{"embedded", "outlined"} {
+		items, _ := m[key].([]interface{})
+		for _, item := range items {
+			cfg, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			pkg := str(cfg, "pkg")
+			if pkg != "" && pkg != "main" {
+				deps.outputs = append(deps.outputs, abs(pkg))
+			}
+			for _, key := range []string{"output", "api"} {
+				if pth := str(cfg, key); pth != "" && pth != OutputToProgram && pth != OutputToStdout {
+					deps.outputs = append(deps.outputs, abs(pth))
+				}
+			}
+			inputs, _ := cfg["inputs"].([]interface{})
+			for _, input := range inputs {
+				if input, ok := input.(map[string]interface{}); ok {
+					if pth := str(input, "path"); pth != "" && !strings.HasPrefix(pth, GoPathPrefix) && !strings.Contains(pth, "{{") {
+						deps.inputs = append(deps.inputs, abs(pth))
+					}
+				}
+			}
+		}
+	}
+
+	// the inputs into own outputs aren't dependencies
+	var inputs []string
+	for _, input := range deps.inputs {
+		var own bool
+		for _, output := range deps.outputs {
+			if pathContains(output, input) {
+				own = true
+				break
+			}
+		}
+		if !own {
+			inputs = append(inputs, input)
+		}
+	}
+	deps.inputs = inputs
+	return
+}
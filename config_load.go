@@ -0,0 +1,367 @@
+package xbindata
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	yaml3 "gopkg.in/yaml.v3"
+)
+
+// EnvProfile is the environment variable of selected config profile.
+const EnvProfile = "XB_PROFILE"
+
+// ConfigPaths is a list of config file paths. Accepts a single path too.
+type ConfigPaths []string
+
+func (ConfigPaths) configScalarSlice() {}
+
+// configScalarSlice is implemented by the slice types that accepts a single
+// value.
+type configScalarSlice interface {
+	configScalarSlice()
+}
+
+var configScalarSliceType = reflect.TypeOf((*configScalarSlice)(nil)).Elem()
+
+// LoadConfigFile loads the YAML or JSON config file, checked by CheckConfig,
+// and resolves the `extends`, `defaults` and `include` directives:
+//
+//	# the base files, merged in order. The maps are merged recursively and
+//	# the other values (lists too) are replaced. This file wins.
+//	extends: ../xb.base.yaml
+//	# the defaults of each embedded and outlined config of this file. The
+//	# config values wins.
+//	defaults:
+//	  fs: true
+//	  default:
+//	    input:
+//	      recursive: true
+//	# the files (or glob patterns) whose embedded and outlined configs are
+//	# appended to the configs of this file.
+//	include:
+//	  - services/*/.xb.assets.yaml
+//
+// The paths are relative to the file that declares them: the `extends` and
+// `include` paths and, of the included and extended files, the `dir`,
+// `output`, `api`, `prefix` and input paths, and the `pkg` derived paths
+// (see ManyConfigCommon.Dir). The other paths are relative to the build
+// directory, as usually.
+//
+// The embedded, outlined, defaults and input configs accepts the `profiles`
+// overrides, merged over the config if the profile is selected:
+//
+//	outlined:
+//	  - pkg: assets
+//	    no_compress: true
+//	    profiles:
+//	      prod:
+//	        no_compress: false
+//	    inputs:
+//	      - path: assets
+//	        profiles:
+//	          prod:
+//	            ignore_glob: ["*.map"]
+//
+// The profile not defined by the files is ignored, with a warning, so
+// XB_PROFILE may be exported for many projects.
+//
+// Returns the resolved config map, without the directives.
+func LoadConfigFile(pth string, profile ...string) (config map[string]interface{}, err error) {
+	if pth, err = filepath.Abs(pth); err != nil {
+		return
+	}
+	var l configLoader
+	if len(profile) > 0 {
+		l.profile = profile[0]
+	}
+	if config, err = l.load(pth, nil); err != nil {
+		return
+	}
+	if len(l.errs) > 0 {
+		return nil, l.errs
+	}
+	if l.profile != "" && !l.profileFound {
+		// the profile may be exported for other config files
+		log.Warnf("%s: profile %q is not defined", pth, l.profile)
+	}
+	return
+}
+
+type configLoader struct {
+	errs         ConfigErrors
+	profile      string
+	profileFound bool
+}
+
+// applyProfile merges the selected profile overrides of value maps,
+// recursively, and removes the `profiles` keys.
+func (l *configLoader) applyProfile(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		profiles, _ := v["profiles"].(map[string]interface{})
+		delete(v, "profiles")
+		for key, value := range v {
+			v[key] = l.applyProfile(value)
+		}
+		if override, ok := profiles[l.profile]; ok && l.profile != "" {
+			l.profileFound = true
+			if override, ok := override.(map[string]interface{}); ok {
+				v = configMerge(v, l.applyProfile(override).(map[string]interface{}))
+			}
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = l.applyProfile(item)
+		}
+	}
+	return value
+}
+
+// raw reads the file and merges the extends bases.
+func (l *configLoader) raw(pth string, stack []string) (m map[string]interface{}, err error) {
+	for _, p := range stack {
+		if p == pth {
+			return nil, fmt.Errorf("config cycle: %s -> %s", strings.Join(stack, " -> "), pth)
+		}
+	}
+	stack = append(stack, pth)
+
+	var data []byte
+	if data, err = ioutil.ReadFile(pth); err != nil {
+		return
+	}
+	if err = CheckConfig(pth, data); err != nil {
+		if errs, ok := err.(ConfigErrors); ok {
+			l.errs = append(l.errs, errs...)
+			err = nil
+		} else {
+			return
+		}
+	}
+	if err = yaml3.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", pth, err)
+	}
+	if m == nil {
+		m = map[string]interface{}{}
+	}
+
+	var extends []string
+	if extends, err = configDirectivePaths(pth, m, "extends"); err != nil {
+		return
+	}
+	delete(m, "extends")
+
+	base := map[string]interface{}{}
+	for _, ext := range extends {
+		var em map[string]interface{}
+		if em, err = l.raw(ext, stack); err != nil {
+			return
+		}
+		// the include paths are resolved by the declaring file
+		if inc, ok := em["include"]; ok {
+			em["include"] = configAbsPaths(filepath.Dir(ext), inc)
+		}
+		configRebase(filepath.Dir(ext), em)
+		base = configMerge(base, em)
+	}
+	if inc, ok := m["include"]; ok {
+		m["include"] = configAbsPaths(filepath.Dir(pth), inc)
+	}
+	return configMerge(base, m), nil
+}
+
+// load reads the file, merges the extends bases, applies the defaults and
+// appends the includes configs.
+func (l *configLoader) load(pth string, stack []string) (m map[string]interface{}, err error) {
+	for _, p := range stack {
+		if p == pth {
+			return nil, fmt.Errorf("config include cycle: %s -> %s", strings.Join(stack, " -> "), pth)
+		}
+	}
+	stack = append(stack, pth)
+
+	if m, err = l.raw(pth, nil); err != nil {
+		return
+	}
+	m = l.applyProfile(m).(map[string]interface{})
+
+	defaults, _ := m["defaults"].(map[string]interface{})
+	delete(m, "defaults")
+
+	var includes []string
+	if includes, err = configDirectivePaths(pth, m, "include"); err != nil {
+		return
+	}
+	delete(m, "include")
+
+	pkgs := map[string]string{}
+
+	for _, key := range []string{"embedded", "outlined"} {
+		items, _ := m[key].([]interface{})
+		for i, item := range items {
+			if item, ok := item.(map[string]interface{}); ok {
+				if defaults != nil {
+					items[i] = configMerge(configMerge(map[string]interface{}{}, defaults), item)
+				}
+				pkgs[key+":"+fmt.Sprint(items[i].(map[string]interface{})["pkg"])] = pth
+			}
+		}
+	}
+
+	for _, inc := range includes {
+		var matches []string
+		if matches, err = filepath.Glob(inc); err != nil {
+			return nil, fmt.Errorf("%s: include %q: %v", pth, inc, err)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(inc, "*?[") {
+			return nil, fmt.Errorf("%s: include %q: file does not exists", pth, inc)
+		}
+		sort.Strings(matches)
+
+		for _, match := range matches {
+			var im map[string]interface{}
+			if im, err = l.load(match, stack); err != nil {
+				return
+			}
+			configRebase(filepath.Dir(match), im)
+			for _, key := range []string{"embedded", "outlined"} {
+				items, _ := im[key].([]interface{})
+				for _, item := range items {
+					pkg := key + ":"
+					if item, ok := item.(map[string]interface{}); ok {
+						pkg += fmt.Sprint(item["pkg"])
+					}
+					if other, ok := pkgs[pkg]; ok {
+						return nil, fmt.Errorf("%s: duplicate %s config of package %q, also defined in %s",
+							match, key, strings.TrimPrefix(pkg, key+":"), other)
+					}
+					pkgs[pkg] = match
+				}
+				if len(items) > 0 {
+					list, _ := m[key].([]interface{})
+					m[key] = append(list, items...)
+				}
+			}
+		}
+	}
+	return
+}
+
+// configDirectivePaths returns the absolute paths of directive value.
+func configDirectivePaths(pth string, m map[string]interface{}, key string) (paths []string, err error) {
+	switch v := configAbsPaths(filepath.Dir(pth), m[key]).(type) {
+	case nil:
+	case string:
+		paths = []string{v}
+	case []interface{}:
+		for _, p := range v {
+			if s, ok := p.(string); ok {
+				paths = append(paths, s)
+			} else {
+				return nil, fmt.Errorf("%s: bad %s path %v", pth, key, p)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("%s: bad %s value %v", pth, key, v)
+	}
+	return
+}
+
+// configAbsPaths joins the relative paths of value (path or list of paths)
+// to dir.
+func configAbsPaths(dir string, value interface{}) interface{} {
+	abs := func(p interface{}) interface{} {
+		if s, ok := p.(string); ok && !filepath.IsAbs(s) {
+			return filepath.Join(dir, filepath.FromSlash(s))
+		}
+		return p
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		r := make([]interface{}, len(v))
+		for i, p := range v {
+			r[i] = abs(p)
+		}
+		return r
+	default:
+		return abs(v)
+	}
+}
+
+// configRebase joins the relative paths of embedded, outlined and defaults
+// configs of m, of file of dir, to dir. The embedded and outlined configs
+// without `dir` gets dir. The paths already absolute, rebased by the nested
+// files, are kept.
+func configRebase(dir string, m map[string]interface{}) {
+	if defaults, ok := m["defaults"].(map[string]interface{}); ok {
+		configRebaseItem(dir, defaults)
+	}
+	for _, key := range []string{"embedded", "outlined"} {
+		items, _ := m[key].([]interface{})
+		for _, item := range items {
+			if item, ok := item.(map[string]interface{}); ok {
+				configRebaseItem(dir, item)
+				if _, ok := item["dir"]; !ok {
+					item["dir"] = dir
+				}
+			}
+		}
+	}
+}
+
+// configRebaseItem joins the relative paths of config item and of its
+// inputs and profiles overrides to dir.
+func configRebaseItem(dir string, item map[string]interface{}) {
+	abs := func(m map[string]interface{}, keys ...string) {
+		for _, key := range keys {
+			// the `_` prefix is the first input path
+			if s, ok := m[key].(string); ok && s != "" && s != OutputToProgram && s != OutputToStdout &&
+				!strings.HasPrefix(s, GoPathPrefix) {
+				m[key] = configAbsPaths(dir, s)
+			}
+		}
+	}
+	abs(item, "dir", "output", "api", "prefix")
+
+	inputs, _ := item["inputs"].([]interface{})
+	for _, input := range inputs {
+		if input, ok := input.(map[string]interface{}); ok {
+			abs(input, "path", "prefix")
+			if profiles, ok := input["profiles"].(map[string]interface{}); ok {
+				for _, override := range profiles {
+					if override, ok := override.(map[string]interface{}); ok {
+						abs(override, "path", "prefix")
+					}
+				}
+			}
+		}
+	}
+	if profiles, ok := item["profiles"].(map[string]interface{}); ok {
+		for _, override := range profiles {
+			if override, ok := override.(map[string]interface{}); ok {
+				configRebaseItem(dir, override)
+			}
+		}
+	}
+}
+
+// configMerge merges src into dst recursively and returns dst. The src
+// values wins, except for maps, which are merged.
+func configMerge(dst, src map[string]interface{}) map[string]interface{} {
+	for key, value := range src {
+		if sm, ok := value.(map[string]interface{}); ok {
+			if dm, ok := dst[key].(map[string]interface{}); ok {
+				dst[key] = configMerge(configMerge(map[string]interface{}{}, dm), sm)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+	return dst
+}
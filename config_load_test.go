@@ -0,0 +1,107 @@
+package xbindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfigFileRebase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, data := range map[string]string{
+		"xb.base.yaml": `
+outlined:
+  - pkg: base
+    output: base.xb
+`,
+		".xb.yaml": `
+extends: xb.base.yaml
+include: services/*/.xb.yaml
+embedded:
+  - pkg: root
+    inputs:
+      - path: static
+`,
+		"services/a/.xb.yaml": `
+embedded:
+  - pkg: assets
+    prefix: static
+    inputs:
+      - path: static
+      - path: go:example.com/ui
+      - path: /abs/static
+    profiles:
+      prod:
+        output: prod/assets.go
+`,
+	} {
+		pth := filepath.Join(dir, name)
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m, err := LoadConfigFile(filepath.Join(dir, ".xb.yaml"), "prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := filepath.Join(dir, "services", "a")
+	want := map[string]interface{}{
+		"outlined": []interface{}{
+			map[string]interface{}{"pkg": "base", "output": filepath.Join(dir, "base.xb"), "dir": dir},
+		},
+		"embedded": []interface{}{
+			map[string]interface{}{"pkg": "root", "inputs": []interface{}{map[string]interface{}{"path": "static"}}},
+			map[string]interface{}{
+				"pkg":    "assets",
+				"dir":    svc,
+				"prefix": filepath.Join(svc, "static"),
+				"output": filepath.Join(svc, "prod", "assets.go"),
+				"inputs": []interface{}{
+					map[string]interface{}{"path": filepath.Join(svc, "static")},
+					map[string]interface{}{"path": "go:example.com/ui"},
+					map[string]interface{}{"path": "/abs/static"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("have config\n%v\nwant\n%v", m, want)
+	}
+
+	// the pkg derived paths are relative to dir
+	var a ManyConfigEmbedded
+	a.Pkg, a.Dir = "assets", svc
+	a.Validate()
+	if a.Output != filepath.Join(svc, "assets", "assets.go") || a.Inputs[0].Path != filepath.Join(svc, "assets", DefaultDataDir) {
+		t.Errorf("bad derived paths %q %q", a.Output, a.Inputs[0].Path)
+	}
+}
+
+func TestLoadConfigFileUndefinedProfile(t *testing.T) {
+	f, err := ioutil.TempFile("", "xbprofile*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("embedded:\n  - pkg: assets\n    profiles:\n      prod:\n        no_compress: true\n")
+	f.Close()
+
+	m, err := LoadConfigFile(f.Name(), "staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{map[string]interface{}{"pkg": "assets"}}; !reflect.DeepEqual(m["embedded"], want) {
+		t.Errorf("have embedded %v, want %v", m["embedded"], want)
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/pkg/errors"
 
 	"github.com/apex/log"
@@ -17,6 +19,7 @@ import (
 
 	path_helpers "github.com/moisespsena-go/path-helpers"
 	"github.com/moisespsena-go/xbindata/ignore"
+	"github.com/moisespsena-go/xbindata/walker"
 
 	"github.com/mitchellh/mapstructure"
 )
@@ -38,7 +41,7 @@ func (s ManyConfigInputSlice) Items(ctx context.Context) (r []InputConfig, err e
 	for j, input := range s {
 		ctx := ContextWithInputKey(ctx, "#"+strconv.Itoa(j))
 		if c, err := input.Config(ctx); err != nil {
-			return nil, fmt.Errorf("get config from input #%d (%q) failed: %v", j, input, err)
+			return nil, fmt.Errorf("get config from input #%d (%v) failed: %v", j, input, err)
 		} else {
 			for _, c := range c {
 				r = append(r, *c)
@@ -57,6 +60,20 @@ type ManyConfigInput struct {
 	DirReplacesCount int
 	IgnoreGlob       IgnoreGlobSlice `mapstructure:"ignore_glob" yaml:"ignore_glob"`
 	Pkg              string
+	// Override marks the input assets as winners on name collisions.
+	Override bool
+	// Symlinks is the symlinks policy. See InputConfig.Symlinks.
+	Symlinks string
+	// Subdir is the archive directory. See InputConfig.Subdir.
+	Subdir string
+	// Walker is the in-process walker name. See InputConfig.Walker.
+	Walker string
+	// Profiles are the input overrides of each profile. See
+	// LoadConfigFile.
+	Profiles map[string]ManyConfigInput
+
+	// profile is the selected config profile, passed to `.xbwalk` program.
+	profile string
 }
 
 func (i *ManyConfigInput) UnmarshalMap(value interface{}) (err error) {
@@ -98,9 +115,12 @@ func (i ManyConfigInput) Config(ctx context.Context) (configs []*InputConfig, er
 		}()
 	}
 
-	if strings.HasPrefix(i.Path, "go:") {
-		i.Pkg = i.Path[3:]
-		_, i.Path = path_helpers.ResolveGoSrcPath(i.Pkg)
+	i.profile = ContextProfile(ctx)
+
+	if strings.HasPrefix(i.Path, GoPathPrefix) {
+		if i.Pkg, i.Path, err = ResolveGoPath(i.Path[len(GoPathPrefix):]); err != nil {
+			return
+		}
 	}
 
 	if i.Path, err = i.format(ctx, "path", i.Path); err != nil {
@@ -197,6 +217,14 @@ func (i ManyConfigInput) Config(ctx context.Context) (configs []*InputConfig, er
 				input.Recursive = false
 			}
 
+			if i.Override {
+				input.Override = true
+			}
+
+			if input.Symlinks == "" {
+				input.Symlinks = i.Symlinks
+			}
+
 			input.IgnoreGlob = append(i.IgnoreGlob, input.IgnoreGlob...)
 			input.Ignore = append(i.Ignore, input.Ignore...)
 
@@ -216,6 +244,11 @@ func (i ManyConfigInput) Config(ctx context.Context) (configs []*InputConfig, er
 		Prefix:           i.Prefix,
 		NameSpace:        i.NameSpace,
 		DirReplacesCount: i.DirReplacesCount,
+		Override:         i.Override,
+		Symlinks:         i.Symlinks,
+		Subdir:           i.Subdir,
+		Walker:           i.Walker,
+		Profile:          i.profile,
 	}
 
 	if i.Prefix == "_" {
@@ -229,8 +262,8 @@ func (i ManyConfigInput) Config(ctx context.Context) (configs []*InputConfig, er
 		return nil, err
 	}
 
-	walkedPath := filepath.Join(i.Path, ".xbwalk", "main.go")
-	if _, err := os.Stat(walkedPath); err == nil {
+	walkedPath := filepath.Join(i.Path, walker.XbWalkName, "main.go")
+	if _, err := os.Stat(walkedPath); err == nil && c.Walker == "" {
 		c.WalkFunc = i.Walked
 	}
 
@@ -265,23 +298,49 @@ func (d *ManyConfigCommonDefault) UnmarshalMap(value interface{}) (err error) {
 }
 
 type ManyConfigCommon struct {
-	Disabled        bool
-	NoAutoLoad      bool `mapstructure:"no_auto_load" yaml:"no_auto_load"`
-	NoCompress      bool `mapstructure:"no_compress" yaml:"no_compress"`
-	NoMetadata      bool `mapstructure:"no_metadata" yaml:"no_metadata"`
-	NoMemCopy       bool `mapstructure:"no_mem_copy" yaml:"no_mem_copy"`
-	Mode            uint
-	ModTime         int64 `mapstructure:"mod_time" yaml:"mod_time"`
-	Ignore          IgnoreSlice
-	IgnoreGlob      IgnoreGlobSlice `mapstructure:"ignore_glob" yaml:"ignore_glob"`
-	Inputs          ManyConfigInputSlice
-	Pkg             string
+	Disabled   bool
+	NoAutoLoad bool `mapstructure:"no_auto_load" yaml:"no_auto_load"`
+	NoCompress bool `mapstructure:"no_compress" yaml:"no_compress"`
+	NoMetadata bool `mapstructure:"no_metadata" yaml:"no_metadata"`
+	NoMemCopy  bool `mapstructure:"no_mem_copy" yaml:"no_mem_copy"`
+	Mode       uint
+	ModTime    int64 `mapstructure:"mod_time" yaml:"mod_time"`
+	Ignore     IgnoreSlice
+	IgnoreGlob IgnoreGlobSlice `mapstructure:"ignore_glob" yaml:"ignore_glob"`
+	Inputs     ManyConfigInputSlice
+	Pkg        string
+	// Dir is the base directory of the default inputs and output, derived
+	// from Pkg. Defaults to the build directory. LoadConfigFile sets it to
+	// the directory of included and extended files.
+	Dir             string
 	Output          string
 	Prefix          string
 	Hybrid          bool
 	Fs              bool
 	FsLoadCallbacks []string `mapstructure:"fs_load_callbacks" yaml:"fs_load_callbacks"`
 	Default         ManyConfigCommonDefault
+	Budget          *ManyConfigBudget
+	IntegritySHA384 bool `mapstructure:"integrity_sha384" yaml:"integrity_sha384"`
+	Checksums       bool
+	SelfTest        bool `mapstructure:"self_test" yaml:"self_test"`
+	Collisions      Collisions
+	PreserveDirs    bool `mapstructure:"preserve_dirs" yaml:"preserve_dirs"`
+	// Metadata are the asset metadata rules. See Config.Metadata.
+	Metadata         []MetadataRule
+	MetadataSidecars bool `mapstructure:"metadata_sidecars" yaml:"metadata_sidecars"`
+	ContentTypes     bool `mapstructure:"content_types" yaml:"content_types"`
+	// ShardSize accepts human readable values, example: `32MB`. See
+	// Config.ShardSize.
+	ShardSize string `mapstructure:"shard_size" yaml:"shard_size"`
+	// Accessors accepts `const` or `tree`. See Config.Accessors.
+	Accessors string
+	// HashedNames accepts `add` or `only`. See Config.HashedNames.
+	HashedNames         string `mapstructure:"hashed_names" yaml:"hashed_names"`
+	HashedNamesLength   int    `mapstructure:"hashed_names_length" yaml:"hashed_names_length"`
+	HashedNamesManifest string `mapstructure:"hashed_names_manifest" yaml:"hashed_names_manifest"`
+	// Profiles are the config overrides of each profile. See
+	// LoadConfigFile.
+	Profiles map[string]ManyConfigCommon
 }
 
 func (a *ManyConfigCommon) Validate() (err error) {
@@ -291,7 +350,7 @@ func (a *ManyConfigCommon) Validate() (err error) {
 	if a.Pkg == "main" {
 		if len(a.Inputs) == 0 {
 			a.Inputs = append(a.Inputs, ManyConfigInput{
-				Path:       "assets",
+				Path:       filepath.Join(a.Dir, "assets"),
 				Recursive:  true,
 				IgnoreGlob: IgnoreGlobSlice{".*", "*.swp"},
 			})
@@ -299,7 +358,7 @@ func (a *ManyConfigCommon) Validate() (err error) {
 	} else {
 		if len(a.Inputs) == 0 {
 			a.Inputs = append(a.Inputs, ManyConfigInput{
-				Path:       filepath.Join(filepath.FromSlash(a.Pkg), DefaultDataDir),
+				Path:       filepath.Join(a.Dir, filepath.FromSlash(a.Pkg), DefaultDataDir),
 				Recursive:  true,
 				IgnoreGlob: IgnoreGlobSlice{".*", "*.swp"},
 			})
@@ -313,41 +372,75 @@ func (a *ManyConfigCommon) Validate() (err error) {
 	return nil
 }
 
-func (a *ManyConfigCommon) Config(ctx context.Context) (c *Config, err error) {
-	c = NewConfig()
-	c.Package = a.Pkg
-	c.FileSystem = a.Fs
-	c.FileSystemLoadCallbacks = a.FsLoadCallbacks
-	c.NoAutoLoad = a.NoAutoLoad
-	c.NoCompress = a.NoCompress
-	c.NoMetadata = a.NoMetadata
-	c.NoMemCopy = a.NoMemCopy
-	c.Mode = a.Mode
-	c.ModTime = a.ModTime
-	c.Prefix = a.Prefix
-	c.Hybrid = a.Hybrid
+// Options returns the Builder options of config.
+func (a *ManyConfigCommon) Options(ctx context.Context) (opts []Option, err error) {
+	opts = append(opts,
+		WithPackage(a.Pkg),
+		WithPrefix(a.Prefix),
+		WithMode(a.Mode),
+		WithIgnore(a.Ignore...),
+		WithIgnoreGlob(a.IgnoreGlob...),
+		WithMetadata(a.Metadata...),
+		WithCollisions(a.Collisions),
+		WithAccessors(a.Accessors),
+		WithHashedNames(a.HashedNames, a.HashedNamesLength, a.HashedNamesManifest),
+		WithProfile(ContextProfile(ctx)),
+	)
 
 	if a.Output != "" {
-		c.Output = a.Output
+		opts = append(opts, WithOutput(a.Output))
+	}
+	if a.ModTime != 0 {
+		opts = append(opts, WithModTime(time.Unix(a.ModTime, 0)))
+	}
+	if a.ShardSize != "" {
+		var size uint64
+		if size, err = humanize.ParseBytes(a.ShardSize); err != nil {
+			return nil, errors.Wrapf(err, "parse shard_size")
+		}
+		opts = append(opts, WithShardSize(int64(size)))
+	}
+
+	for _, opt := range []struct {
+		enabled bool
+		opt     func() Option
+	}{
+		{a.NoAutoLoad, WithNoAutoLoad},
+		{a.NoCompress, WithNoCompress},
+		{a.NoMetadata, WithNoMetadata},
+		{a.NoMemCopy, WithNoMemCopy},
+		{a.Hybrid, WithHybrid},
+		{a.IntegritySHA384, WithIntegritySHA384},
+		{a.Checksums, WithChecksums},
+		{a.SelfTest, WithSelfTest},
+		{a.PreserveDirs, WithPreserveDirs},
+		{a.MetadataSidecars, WithMetadataSidecars},
+		{a.ContentTypes, WithContentTypes},
+	} {
+		if opt.enabled {
+			opts = append(opts, opt.opt())
+		}
 	}
 
-	if len(a.FsLoadCallbacks) > 0 {
-		var cwd, _ = os.Getwd()
-		gph := path_helpers.PkgFromPath(cwd)
-
+	if a.Fs || len(a.FsLoadCallbacks) > 0 {
+		var (
+			callbacks = make([]string, len(a.FsLoadCallbacks))
+			cwd, _    = os.Getwd()
+			gph       = path_helpers.PkgFromPath(cwd)
+		)
 		for i, cb := range a.FsLoadCallbacks {
 			if cb[0] == '.' {
 				cb = path.Join(gph, cb[1:])
-				c.FileSystemLoadCallbacks[i] = cb
 			}
+			callbacks[i] = cb
+		}
+		if a.Fs {
+			opts = append(opts, WithFileSystem(callbacks...))
+		} else {
+			opts = append(opts, WithConfig(func(c *Config) {
+				c.FileSystemLoadCallbacks = callbacks
+			}))
 		}
-	}
-
-	if c.IgnoreGlob, err = a.IgnoreGlob.Items(); err != nil {
-		return nil, err
-	}
-	if c.Ignore, err = a.Ignore.Items(); err != nil {
-		return nil, err
 	}
 
 	for i, input := range a.Inputs {
@@ -363,17 +456,107 @@ func (a *ManyConfigCommon) Config(ctx context.Context) (c *Config, err error) {
 		a.Inputs[i] = input
 	}
 
-	if c.Input, err = a.Inputs.Items(ctx); err != nil {
+	var inputs []InputConfig
+	if inputs, err = a.Inputs.Items(ctx); err != nil {
 		return nil, err
 	}
+	opts = append(opts, WithInput(inputs...))
 
+	if a.Budget != nil {
+		var budget *Budget
+		if budget, err = a.Budget.Budget(); err != nil {
+			return nil, errors.Wrapf(err, "budget")
+		}
+		opts = append(opts, WithBudget(budget))
+		if a.Budget.WarnOnly {
+			opts = append(opts, WithBudgetWarnOnly())
+		}
+	}
 	return
 }
 
+// Config returns the Translate config.
+func (a *ManyConfigCommon) Config(ctx context.Context) (c *Config, err error) {
+	var b *Builder
+	if b, err = a.Builder(ctx); err != nil {
+		return
+	}
+	return b.Config(), nil
+}
+
+// Builder returns the builder of config, with the extra options.
+func (a *ManyConfigCommon) Builder(ctx context.Context, opts ...Option) (b *Builder, err error) {
+	var configOpts []Option
+	if configOpts, err = a.Options(ctx); err != nil {
+		return
+	}
+	return NewBuilder(append(configOpts, opts...)...)
+}
+
 func (a *ManyConfigCommon) UnmarshalMap(value interface{}) (err error) {
 	return mapstructure.Decode(value, a)
 }
 
+// ManyConfigBudgetLimits defines the size limits. Sizes accepts human
+// readable values, example: `512KB`, `10 MiB`.
+type ManyConfigBudgetLimits struct {
+	MaxTotalSize string `mapstructure:"max_total_size" yaml:"max_total_size"`
+	MaxFileSize  string `mapstructure:"max_file_size" yaml:"max_file_size"`
+	MaxFiles     int    `mapstructure:"max_files" yaml:"max_files"`
+}
+
+func (l ManyConfigBudgetLimits) Limits() (r BudgetLimits, err error) {
+	var size uint64
+	if l.MaxTotalSize != "" {
+		if size, err = humanize.ParseBytes(l.MaxTotalSize); err != nil {
+			return r, errors.Wrapf(err, "parse max_total_size")
+		}
+		r.MaxTotalSize = int64(size)
+	}
+	if l.MaxFileSize != "" {
+		if size, err = humanize.ParseBytes(l.MaxFileSize); err != nil {
+			return r, errors.Wrapf(err, "parse max_file_size")
+		}
+		r.MaxFileSize = int64(size)
+	}
+	r.MaxFiles = l.MaxFiles
+	return
+}
+
+// ManyConfigBudget defines the size limits of the package and of each name
+// space.
+//
+//	budget:
+//	  max_total_size: 20MB
+//	  max_file_size: 2MB
+//	  max_files: 500
+//	  name_spaces:
+//	    static/videos:
+//	      max_total_size: 100MB
+type ManyConfigBudget struct {
+	MaxTotalSize string                            `mapstructure:"max_total_size" yaml:"max_total_size"`
+	MaxFileSize  string                            `mapstructure:"max_file_size" yaml:"max_file_size"`
+	MaxFiles     int                               `mapstructure:"max_files" yaml:"max_files"`
+	NameSpaces   map[string]ManyConfigBudgetLimits `mapstructure:"name_spaces" yaml:"name_spaces"`
+	WarnOnly     bool                              `mapstructure:"warn_only" yaml:"warn_only"`
+}
+
+func (b *ManyConfigBudget) Budget() (r *Budget, err error) {
+	r = &Budget{}
+	if r.BudgetLimits, err = (ManyConfigBudgetLimits{b.MaxTotalSize, b.MaxFileSize, b.MaxFiles}).Limits(); err != nil {
+		return nil, err
+	}
+	if len(b.NameSpaces) > 0 {
+		r.NameSpaces = make(map[string]BudgetLimits, len(b.NameSpaces))
+		for ns, l := range b.NameSpaces {
+			if r.NameSpaces[ns], err = l.Limits(); err != nil {
+				return nil, errors.Wrapf(err, "name space %q", ns)
+			}
+		}
+	}
+	return
+}
+
 type ManyConfigEmbedded struct {
 	ManyConfigCommon
 }
@@ -384,9 +567,9 @@ func (a *ManyConfigEmbedded) Validate() (err error) {
 	}
 	if a.Output == "" {
 		if a.Pkg == "main" {
-			a.Output = "assets.go"
+			a.Output = filepath.Join(a.Dir, "assets.go")
 		} else {
-			a.Output = filepath.Join(filepath.FromSlash(a.Pkg), "assets.go")
+			a.Output = filepath.Join(a.Dir, filepath.FromSlash(a.Pkg), "assets.go")
 		}
 	}
 
@@ -401,6 +584,9 @@ type ManyConfigOutlined struct {
 	ManyConfigCommon
 	Api     string
 	Program bool
+	// Profiles are the config overrides of each profile. See
+	// LoadConfigFile.
+	Profiles map[string]ManyConfigOutlined
 }
 
 func (a *ManyConfigOutlined) Validate() (err error) {
@@ -409,30 +595,48 @@ func (a *ManyConfigOutlined) Validate() (err error) {
 	}
 	if a.Pkg == "main" {
 		if a.Api == "" {
-			a.Api = "assets.go"
+			a.Api = filepath.Join(a.Dir, "assets.go")
 		}
 	} else if a.Api == "" {
-		a.Api = filepath.Join(filepath.FromSlash(a.Pkg), "assets.go")
+		a.Api = filepath.Join(a.Dir, filepath.FromSlash(a.Pkg), "assets.go")
 	}
 
 	return nil
 }
 
+// Options returns the Builder options of outlined config.
+func (a *ManyConfigOutlined) Options(ctx context.Context) (opts []Option, err error) {
+	if opts, err = a.ManyConfigCommon.Options(ctx); err != nil {
+		return
+	}
+	opts = append(opts, WithOutlined(a.Api))
+	if a.Program {
+		opts = append(opts, WithOutlinedProgram())
+	}
+
+	output := a.Output
+	if output == "" && !a.Program {
+		output = filepath.Join(a.Dir, filepath.FromSlash(a.Pkg)) + ".xb"
+	}
+	return append(opts, WithOutput(output)), nil
+}
+
+// Config returns the Translate config of outlined config.
 func (a *ManyConfigOutlined) Config(ctx context.Context) (c *Config, err error) {
-	if c, err = a.ManyConfigCommon.Config(ctx); err != nil {
+	var b *Builder
+	if b, err = a.Builder(ctx); err != nil {
 		return
 	}
-	c.Outlined = true
-	c.OutlinedApi = a.Api
-	c.OutlinedProgram = a.Program
-	c.Output = ""
+	return b.Config(), nil
+}
 
-	if a.Output == "" && !a.Program {
-		c.Output = filepath.Join(c.OutlinedOutputDir, filepath.FromSlash(a.Pkg)+".xb")
-	} else {
-		c.Output = a.Output
+// Builder returns the builder of outlined config, with the extra options.
+func (a *ManyConfigOutlined) Builder(ctx context.Context, opts ...Option) (b *Builder, err error) {
+	var configOpts []Option
+	if configOpts, err = a.Options(ctx); err != nil {
+		return
 	}
-	return
+	return NewBuilder(append(configOpts, opts...)...)
 }
 
 func (a *ManyConfigOutlined) UnmarshalMap(value interface{}) (err error) {
@@ -444,14 +648,29 @@ func (a *ManyConfigOutlined) UnmarshalMap(value interface{}) (err error) {
 }
 
 func (a *ManyConfigOutlined) Translate(ctx context.Context) (count int, err error) {
-	var c *Config
-	if c, err = a.Config(ctx); err != nil {
+	var (
+		b      *Builder
+		result *BuildResult
+	)
+	if b, err = a.Builder(ctx); err != nil {
+		return
+	}
+	if result, err = b.Build(ctx); err != nil {
 		return
 	}
-	return Translate(c)
+	return result.Count, nil
 }
 
 type ManyConfig struct {
+	// Extends are the base config files. See LoadConfigFile.
+	Extends ConfigPaths
+	// Defaults are the defaults of each embedded and outlined config. See
+	// LoadConfigFile.
+	Defaults ManyConfigCommon
+	// Include are the files (or glob patterns) whose embedded and outlined
+	// configs are appended. See LoadConfigFile.
+	Include ConfigPaths
+
 	Embedded []ManyConfigEmbedded
 	Outlined []ManyConfigOutlined
 }
@@ -490,7 +709,7 @@ func (c *ManyConfig) Validate() (err error) {
 			hasOutlinedEmbeded = true
 		}
 		if err = cfg.Validate(); err != nil {
-			return fmt.Errorf("Outlined #d validate failed: %v", i, err)
+			return fmt.Errorf("Outlined #%d validate failed: %v", i, err)
 		}
 		outlined = append(outlined, cfg)
 	}
@@ -499,7 +718,7 @@ func (c *ManyConfig) Validate() (err error) {
 			continue
 		}
 		if err = cfg.Validate(); err != nil {
-			return fmt.Errorf("Embeded #d validate failed: %v", i, err)
+			return fmt.Errorf("Embeded #%d validate failed: %v", i, err)
 		}
 		embedded = append(embedded, cfg)
 	}
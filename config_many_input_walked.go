@@ -2,11 +2,17 @@ package xbindata
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 
 	"github.com/go-errors/errors"
@@ -15,25 +21,96 @@ import (
 	"github.com/moisespsena-go/xbindata/walker"
 )
 
+// WalkerCacheDir returns the directory of compiled `.xbwalk` programs.
+// Defaults to `xbindata/walkers` into the user cache directory, or the
+// XB_WALKER_CACHE_DIR environment variable value if defined.
+var WalkerCacheDir = func() (dir string, err error) {
+	if dir = os.Getenv("XB_WALKER_CACHE_DIR"); dir != "" {
+		return
+	}
+	if dir, err = os.UserCacheDir(); err != nil {
+		return
+	}
+	return filepath.Join(dir, "xbindata", "walkers"), nil
+}
+
+// Walked walks the entries written by the `.xbwalk` program of input path.
+// See walker.Entry for the program output protocol. The program is compiled
+// once, and cached by the hash of its local sources, go.mod and go.sum
+// files.
 func (i ManyConfigInput) Walked(_ *map[string]bool, prod, _ bool, cb walker.WalkCallback) (err error) {
-	dir := filepath.Join(i.Path, ".xbwalk")
+	dir := filepath.Join(i.Path, walker.XbWalkName)
 	defer func() {
 		if err != nil {
 			err = fmt.Errorf("%s: %v", dir, err)
 		}
 	}()
 
-	var suffix, exe string
+	var exe string
+	if exe, err = buildWalker(dir); err != nil {
+		return
+	}
+
+	var args []string
+	if prod {
+		args = append(args, "prod")
+	}
+	cmd := exec.Command(exe, args...)
+	cmd.Dir = i.Path
+	cmd.Env = append(os.Environ(), EnvProfile+"="+i.profile)
+	cmd.Stderr = os.Stderr
+
+	var out io.ReadCloser
+	if out, err = cmd.StdoutPipe(); err != nil {
+		return
+	}
+	if err = cmd.Start(); err != nil {
+		return
+	}
+
+	if err = walker.ReadEntries(out, func(entry walker.Entry) error {
+		return walkEntry(i.Path, entry, cb)
+	}); err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return
+	}
+	return cmd.Wait()
+}
+
+// buildWalker returns the compiled program of walker dir, building it if
+// not cached.
+func buildWalker(dir string) (exe string, err error) {
+	var sum, cacheDir, suffix string
+	if sum, err = walkerSum(dir); err != nil {
+		return
+	}
+	if cacheDir, err = WalkerCacheDir(); err != nil {
+		return
+	}
 	if runtime.GOOS == "windows" {
 		suffix = ".exe"
 	}
 
-	if exe, err = tempfile.TempFile("", "xbindata-walk", suffix); err != nil {
+	exe = filepath.Join(cacheDir, sum+suffix)
+	if _, err = os.Stat(exe); err == nil {
+		return
+	} else if !os.IsNotExist(err) {
 		return
 	}
 
-	cmd := exec.Command("go", "build", "-tags", "dev", "-o", exe, filepath.Join(dir, "main.go"))
-	cmd.Dir = i.Path
+	if err = os.MkdirAll(cacheDir, 0755); err != nil {
+		return
+	}
+
+	var tmp string
+	if tmp, err = tempfile.TempFile(cacheDir, "build-"+sum, suffix); err != nil {
+		return
+	}
+	defer os.Remove(tmp)
+
+	cmd := exec.Command("go", "build", "-tags", "dev", "-o", tmp, ".")
+	cmd.Dir = dir
 	cmd.Env = EnvS(map[string]string{
 		"GOOS":   runtime.GOOS,
 		"GOARCH": runtime.GOARCH,
@@ -45,36 +122,93 @@ func (i ManyConfigInput) Walked(_ *map[string]bool, prod, _ bool, cb walker.Walk
 		err = errors.New("build failed: " + err.Error())
 		return
 	}
+	err = os.Rename(tmp, exe)
+	return
+}
 
-	defer os.Remove(exe)
+// walkerPackage is the `go list -deps -json` output.
+type walkerPackage struct {
+	Dir                                       string
+	Standard                                  bool
+	GoFiles, CgoFiles, CFiles, HFiles, SFiles []string
+	EmbedFiles                                []string
+	Module                                    *struct {
+		Main    bool
+		Replace *struct{ Version string }
+	}
+}
 
-	var args []string
-	if prod {
-		args = append(args, "prod")
+// local returns if the package sources are into the main module or into a
+// local replace directory, so they are not pinned by the go.sum.
+func (p *walkerPackage) local() bool {
+	if p.Standard || p.Module == nil {
+		return !p.Standard
 	}
-	cmd = exec.Command(exe, args...)
-	cmd.Dir = i.Path
-	cmd.Env = os.Environ()
+	return p.Module.Main || (p.Module.Replace != nil && p.Module.Replace.Version == "")
+}
 
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = os.Stderr
+// walkerSum returns the hash of the local files of the walker dir package
+// and of its local dependencies, the go.mod and go.sum files of its module,
+// the go version and the platform.
+func walkerSum(dir string) (sum string, err error) {
+	var (
+		h      = sha256.New()
+		files  []string
+		stderr bytes.Buffer
+		out    []byte
+	)
 
-	if err = cmd.Run(); err != nil {
-		return
+	cmd := exec.Command("go", "list", "-deps", "-json", "-tags", "dev", ".")
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+	if out, err = cmd.Output(); err != nil {
+		return "", fmt.Errorf("go list: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var p walkerPackage
+		if err = dec.Decode(&p); err == io.EOF {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("decode go list: %v", err)
+		}
+		if !p.local() {
+			continue
+		}
+		for _, names := range [][]string{p.GoFiles, p.CgoFiles, p.CFiles, p.HFiles, p.SFiles, p.EmbedFiles} {
+			for _, name := range names {
+				files = append(files, filepath.Join(p.Dir, name))
+			}
+		}
 	}
+	sort.Strings(files)
 
-	for _, pth := range strings.Split(out.String(), "\n") {
-		pth = strings.TrimSpace(pth)
-		pth = filepath.Join(i.Path, pth)
-		var info os.FileInfo
-		if info, err = os.Stat(pth); err != nil {
-			return
+	for mod := dir; ; {
+		if _, err := os.Stat(filepath.Join(mod, "go.mod")); err == nil {
+			files = append(files, filepath.Join(mod, "go.mod"), filepath.Join(mod, "go.sum"))
+			break
 		}
-		if err = cb(walker.FileInfo{FileInfo: info, Path: pth}); err != nil {
-			return
+		parent := filepath.Dir(mod)
+		if parent == mod {
+			break
 		}
+		mod = parent
 	}
 
-	return err
+	version, _ := goCmd("env", "GOVERSION")
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", runtime.GOOS, runtime.GOARCH, version)
+
+	for _, pth := range files {
+		var data []byte
+		if data, err = ioutil.ReadFile(pth); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return
+		}
+		name, _ := filepath.Rel(dir, pth)
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(name), len(data))
+		h.Write(data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
 }
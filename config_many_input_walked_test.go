@@ -0,0 +1,54 @@
+package xbindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWalkerSum(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "xbwalk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	write := func(name, data string) {
+		pth := filepath.Join(tmp, name)
+		if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(pth, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("go.mod", "module example.com/m\n\ngo 1.16\n")
+	write("lib/lib.go", "package lib\n\nconst Name = \"a\"\n")
+	write(".xbwalk/sub/sub.go", "package sub\n\nimport \"example.com/m/lib\"\n\nconst Name = lib.Name\n")
+	write(".xbwalk/main.go", "package main\n\nimport \"example.com/m/.xbwalk/sub\"\n\nfunc main() { println(sub.Name) }\n")
+
+	dir := filepath.Join(tmp, ".xbwalk")
+	sum := func() string {
+		s, err := walkerSum(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	prev := sum()
+	if s := sum(); s != prev {
+		t.Fatalf("unstable sum %s != %s", s, prev)
+	}
+	for _, f := range []struct{ name, data string }{
+		{".xbwalk/sub/sub.go", "package sub\n\nimport \"example.com/m/lib\"\n\nconst Name = \"sub\" + lib.Name\n"},
+		{"lib/lib.go", "package lib\n\nconst Name = \"b\"\n"},
+	} {
+		write(f.name, f.data)
+		if s := sum(); s == prev {
+			t.Errorf("%s change not hashed", f.name)
+		} else {
+			prev = s
+		}
+	}
+}
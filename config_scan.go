@@ -0,0 +1,468 @@
+package xbindata
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v2"
+)
+
+// ScanAssetDirs are the directory names detected by ScanProject as assets.
+var ScanAssetDirs = []string{"static", "templates", "public", "dist", "web", "assets"}
+
+// ScanOutlinedSize is the total size of input files from which ScanProject
+// proposes an outlined config instead of embedded.
+var ScanOutlinedSize int64 = 10 << 20
+
+// ScanIgnoreGlob are the ignore globs of configs proposed from the detected
+// directories.
+var ScanIgnoreGlob = []string{".*", "*.swp", "*~"}
+
+// ScanProposal is an embedded or outlined config proposed by ScanProject.
+type ScanProposal struct {
+	// Source describes why the config was proposed: the detected
+	// directories or the `go:generate` line.
+	Source   string
+	Outlined bool
+	Config   ManyConfigCommon
+	// Files and Size are the count and total size of input files.
+	Files int
+	Size  int64
+}
+
+func (p *ScanProposal) String() string {
+	kind := "embedded"
+	if p.Outlined {
+		kind = "outlined"
+	}
+	var inputs = make([]string, len(p.Config.Inputs))
+	for i, input := range p.Config.Inputs {
+		inputs[i] = input.Path
+	}
+	return fmt.Sprintf("%s %q from %s: %s (%d files, %s)", kind, p.Config.Pkg, p.Source,
+		strings.Join(inputs, ", "), p.Files, humanize.IBytes(uint64(p.Size)))
+}
+
+// ScanProject scans the root directory for the assets directories (see
+// ScanAssetDirs) and the `go:generate` lines of go-bindata (or `xb pack`),
+// and returns the proposed configs, relative to root. The directories
+// skipped by FindConfigFiles, the Go package directories and the inputs of
+// `go:generate` lines aren't detected as assets directories.
+//
+// The detected directories of same parent are proposed as a single package,
+// `PARENT/assets` (or `PARENT/xbassets` if a directory is named `assets`),
+// outlined and hybrid if the inputs size is greater than ScanOutlinedSize.
+func ScanProject(root string) (proposals []*ScanProposal, warnings []string, err error) {
+	var (
+		dirs      []string
+		generated []*ScanProposal
+	)
+
+	err = filepath.Walk(root, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			if strings.HasSuffix(pth, ".go") {
+				p, w, err := scanGoGenerate(root, pth)
+				if err != nil {
+					return err
+				}
+				generated = append(generated, p...)
+				warnings = append(warnings, w...)
+			}
+			return nil
+		}
+		if pth == root {
+			return nil
+		}
+		if skipConfigDir(info.Name()) {
+			return filepath.SkipDir
+		}
+		for _, name := range ScanAssetDirs {
+			if info.Name() == name {
+				if hasGoFiles(pth) {
+					return nil
+				}
+				rel, _ := filepath.Rel(root, pth)
+				dirs = append(dirs, filepath.ToSlash(rel))
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	// the directories of go:generate inputs are already packed
+	var (
+		groups  = map[string][]string{}
+		parents []string
+	)
+dirs:
+	for _, dir := range dirs {
+		for _, p := range generated {
+			for _, input := range p.Config.Inputs {
+				if pathContains(input.Path, dir) || pathContains(dir, input.Path) {
+					continue dirs
+				}
+			}
+		}
+		parent := path.Dir(dir)
+		if _, ok := groups[parent]; !ok {
+			parents = append(parents, parent)
+		}
+		groups[parent] = append(groups[parent], dir)
+	}
+	sort.Strings(parents)
+
+	for _, parent := range parents {
+		p := &ScanProposal{Source: "detected directories"}
+		pkg := "assets"
+		for _, dir := range groups[parent] {
+			if path.Base(dir) == pkg {
+				// the package can't be the input directory
+				pkg = "xbassets"
+			}
+		}
+		if parent != "." {
+			pkg = path.Join(parent, pkg)
+		}
+
+		var maps bool
+		for _, dir := range groups[parent] {
+			input := ManyConfigInput{Path: dir, Prefix: "_", Recursive: true}
+			if len(groups[parent]) > 1 {
+				input.NameSpace = path.Base(dir)
+			}
+			var files int
+			var size int64
+			if files, size, maps, err = scanDirSize(filepath.Join(root, filepath.FromSlash(dir)), maps); err != nil {
+				return
+			}
+			if files == 0 {
+				continue
+			}
+			p.Files += files
+			p.Size += size
+			p.Config.Inputs = append(p.Config.Inputs, input)
+		}
+		if len(p.Config.Inputs) == 0 {
+			continue
+		}
+
+		p.Config.Pkg = pkg
+		p.Config.Fs = true
+		p.Config.IgnoreGlob = append(IgnoreGlobSlice{}, ScanIgnoreGlob...)
+		if maps {
+			p.Config.IgnoreGlob = append(p.Config.IgnoreGlob, "*.map")
+		}
+		p.Outlined = p.Size > ScanOutlinedSize
+		// the dev mode local file system is generated for outlined only
+		p.Config.Hybrid = p.Outlined
+		proposals = append(proposals, p)
+	}
+
+	for _, p := range generated {
+		for _, input := range p.Config.Inputs {
+			files, size, _, err := scanDirSize(filepath.Join(root, filepath.FromSlash(input.Path)), false)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: input %q: %v", p.Source, input.Path, err))
+				continue
+			}
+			p.Files += files
+			p.Size += size
+		}
+	}
+	proposals = append(proposals, generated...)
+	return
+}
+
+// MarshalScanProposals returns the YAML config of proposals.
+func MarshalScanProposals(proposals []*ScanProposal) []byte {
+	var (
+		buf                bytes.Buffer
+		embedded, outlined []*ScanProposal
+	)
+	for _, p := range proposals {
+		if p.Outlined {
+			outlined = append(outlined, p)
+		} else {
+			embedded = append(embedded, p)
+		}
+	}
+
+	fmt.Fprintf(&buf, "# yaml-language-server: $schema=%s\n", ConfigSchemaID)
+	for _, group := range []struct {
+		key       string
+		proposals []*ScanProposal
+	}{{"embedded", embedded}, {"outlined", outlined}} {
+		if len(group.proposals) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "%s:\n", group.key)
+		for _, p := range group.proposals {
+			c := p.Config
+			fmt.Fprintf(&buf, "  # %s (%d files, %s)\n", p.Source, p.Files, humanize.IBytes(uint64(p.Size)))
+			fmt.Fprintf(&buf, "  - pkg: %s\n", scanYAMLValue(c.Pkg))
+			scanWriteString(&buf, "    ", "output", c.Output)
+			scanWriteString(&buf, "    ", "prefix", c.Prefix)
+			scanWriteBool(&buf, "    ", "fs", c.Fs)
+			scanWriteBool(&buf, "    ", "hybrid", c.Hybrid)
+			scanWriteBool(&buf, "    ", "no_compress", c.NoCompress)
+			scanWriteBool(&buf, "    ", "no_mem_copy", c.NoMemCopy)
+			scanWriteBool(&buf, "    ", "no_metadata", c.NoMetadata)
+			scanWriteList(&buf, "    ", "ignore", c.Ignore)
+			scanWriteList(&buf, "    ", "ignore_glob", c.IgnoreGlob)
+			buf.WriteString("    inputs:\n")
+			for _, input := range c.Inputs {
+				fmt.Fprintf(&buf, "      - path: %s\n", scanYAMLValue(input.Path))
+				scanWriteString(&buf, "        ", "prefix", input.Prefix)
+				scanWriteString(&buf, "        ", "ns", input.NameSpace)
+				scanWriteBool(&buf, "        ", "recursive", input.Recursive)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func scanYAMLValue(value string) string {
+	b, _ := yaml.Marshal(value)
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+func scanWriteString(buf *bytes.Buffer, indent, key, value string) {
+	if value != "" {
+		fmt.Fprintf(buf, "%s%s: %s\n", indent, key, scanYAMLValue(value))
+	}
+}
+
+func scanWriteBool(buf *bytes.Buffer, indent, key string, value bool) {
+	if value {
+		fmt.Fprintf(buf, "%s%s: true\n", indent, key)
+	}
+}
+
+func scanWriteList(buf *bytes.Buffer, indent, key string, values []string) {
+	if len(values) > 0 {
+		fmt.Fprintf(buf, "%s%s:\n", indent, key)
+		for _, value := range values {
+			fmt.Fprintf(buf, "%s  - %s\n", indent, scanYAMLValue(value))
+		}
+	}
+}
+
+// skipConfigDir returns if the directory name is skipped by FindConfigFiles
+// and ScanProject.
+func skipConfigDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+		name == "vendor" || name == "node_modules" || name == "testdata"
+}
+
+func hasGoFiles(dir string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	return len(matches) > 0
+}
+
+// scanDirSize returns the count and total size of regular files into dir,
+// and if has source maps.
+func scanDirSize(dir string, maps bool) (files int, size int64, hasMaps bool, err error) {
+	hasMaps = maps
+	err = filepath.Walk(dir, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			files++
+			size += info.Size()
+			if strings.HasSuffix(pth, ".map") {
+				hasMaps = true
+			}
+		}
+		return nil
+	})
+	return
+}
+
+// scanGoGenerate returns the configs proposed from the go-bindata (or
+// `xb pack`) `go:generate` lines of Go file.
+func scanGoGenerate(root, file string) (proposals []*ScanProposal, warnings []string, err error) {
+	var f *os.File
+	if f, err = os.Open(file); err != nil {
+		return
+	}
+	defer f.Close()
+
+	var (
+		rel, _  = filepath.Rel(root, file)
+		dir     = filepath.Dir(file)
+		scanner = bufio.NewScanner(f)
+		line    int
+	)
+	rel = filepath.ToSlash(rel)
+
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if !strings.HasPrefix(text, "//go:generate ") {
+			continue
+		}
+		args := scanGenerateArgs(strings.TrimPrefix(text, "//go:generate "))
+		if len(args) == 0 {
+			continue
+		}
+
+		output := "bindata.go"
+		switch {
+		case len(args) > 1 && path.Base(args[0]) == "xb" && args[1] == "pack":
+			args, output = args[2:], DefaultOutput
+		case len(args) > 2 && args[0] == "go" && args[1] == "run" && strings.Contains(args[2], "bindata"):
+			args = args[3:]
+		case strings.Contains(path.Base(args[0]), "bindata"):
+			args = args[1:]
+		default:
+			continue
+		}
+
+		source := fmt.Sprintf("go:generate in %s:%d", rel, line)
+		p, warning := scanGenerateProposal(root, dir, source, output, args)
+		if warning != "" {
+			warnings = append(warnings, source+": "+warning)
+			continue
+		}
+		proposals = append(proposals, p)
+	}
+	err = scanner.Err()
+	return
+}
+
+// scanGenerateArgs splits the go:generate command line, as the go tool does:
+// by spaces, with double quoted arguments.
+func scanGenerateArgs(line string) (args []string) {
+	var (
+		arg    strings.Builder
+		quoted bool
+		has    bool
+	)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			quoted, has = !quoted, true
+		case c == '\\' && quoted && i+1 < len(line):
+			i++
+			arg.WriteByte(line[i])
+		case (c == ' ' || c == '\t') && !quoted:
+			if has {
+				args = append(args, arg.String())
+				arg.Reset()
+				has = false
+			}
+		default:
+			arg.WriteByte(c)
+			has = true
+		}
+	}
+	if has {
+		args = append(args, arg.String())
+	}
+	return
+}
+
+// scanGenerateValueFlags are the go-bindata flags with value.
+var scanGenerateValueFlags = map[string]bool{
+	"o": true, "pkg": true, "prefix": true, "ignore": true, "tags": true, "mode": true, "modtime": true,
+	"ignore-glob": true, "api": true, "accessors": true, "hashed-names": true, "hashed-names-length": true,
+	"hashed-names-manifest": true,
+}
+
+func scanGenerateProposal(root, dir, source, output string, args []string) (p *ScanProposal, warning string) {
+	p = &ScanProposal{Source: source}
+	var (
+		pkgName = "main"
+		c       = &p.Config
+		relTo   = func(pth string) string {
+			if !filepath.IsAbs(pth) {
+				pth = filepath.Join(dir, filepath.FromSlash(pth))
+			}
+			rel, err := filepath.Rel(root, pth)
+			if err != nil {
+				return filepath.ToSlash(pth)
+			}
+			return filepath.ToSlash(rel)
+		}
+	)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			input := ManyConfigInput{Path: arg}
+			if strings.HasSuffix(arg, "/...") {
+				input.Path, input.Recursive = strings.TrimSuffix(arg, "/..."), true
+			}
+			input.Path = relTo(input.Path)
+			c.Inputs = append(c.Inputs, input)
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		var value string
+		if pos := strings.IndexByte(name, '='); pos >= 0 {
+			name, value = name[:pos], name[pos+1:]
+		} else if scanGenerateValueFlags[name] {
+			if i+1 == len(args) {
+				return nil, fmt.Sprintf("flag -%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "o":
+			output = value
+		case "pkg":
+			pkgName = value
+		case "prefix":
+			c.Prefix = relTo(value)
+		case "ignore":
+			c.Ignore = append(c.Ignore, value)
+		case "ignore-glob":
+			c.IgnoreGlob = append(c.IgnoreGlob, value)
+		case "nocompress":
+			c.NoCompress = value != "false"
+		case "nomemcopy":
+			c.NoMemCopy = value != "false"
+		case "nometadata":
+			c.NoMetadata = value != "false"
+		case "fs":
+			c.Fs = value != "false"
+		case "hybrid":
+			c.Hybrid = value != "false"
+		case "outlined":
+			return nil, "the outlined pack isn't supported, write its config by hand"
+		}
+	}
+
+	if len(c.Inputs) == 0 {
+		return nil, "without inputs"
+	}
+
+	c.Output = relTo(output)
+	pkgDir := path.Dir(c.Output)
+	switch {
+	case pkgDir == "." && pkgName == "main":
+		c.Pkg = "main"
+	case path.Base(pkgDir) == pkgName:
+		c.Pkg = pkgDir
+	default:
+		return nil, fmt.Sprintf("the package %q doesn't match the output directory %q", pkgName, pkgDir)
+	}
+	return p, ""
+}
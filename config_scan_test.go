@@ -0,0 +1,89 @@
+package xbindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanProject(t *testing.T) {
+	root, err := ioutil.TempDir("", "xbscan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	for name, data := range map[string]string{
+		"static/app.js":              "app",
+		"static/app.js.map":          "map",
+		"web/index.html":             "index",
+		"ui/assets/big.css":          strings.Repeat("x", 200),
+		"node_modules/static/lib.js": "lib",
+		"cmd/static/main.go":         "package main",
+		"templates/t.html":           "t",
+		"gen/gen.go":                 "package gen\n\n//go:generate go-bindata -pkg gen -prefix ../templates ../templates/...\n",
+		"bad/bad.go":                 "package bad\n\n//go:generate go-bindata -outlined data/...\n//go:generate go-bindata -pkg other data\n",
+	} {
+		pth := filepath.Join(root, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	defer func(size int64) { ScanOutlinedSize = size }(ScanOutlinedSize)
+	ScanOutlinedSize = 100
+
+	proposals, warnings, err := ScanProject(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var have []string
+	for _, p := range proposals {
+		have = append(have, p.String())
+	}
+	want := []string{
+		`embedded "assets" from detected directories: static, web (3 files, 11 B)`,
+		`outlined "ui/xbassets" from detected directories: ui/assets (1 files, 200 B)`,
+		`embedded "gen" from go:generate in gen/gen.go:3: templates (1 files, 1 B)`,
+	}
+	if strings.Join(have, "\n") != strings.Join(want, "\n") {
+		t.Errorf("have proposals\n%s\nwant\n%s", strings.Join(have, "\n"), strings.Join(want, "\n"))
+	}
+	wantWarnings := []string{
+		"go:generate in bad/bad.go:3: the outlined pack isn't supported, write its config by hand",
+		`go:generate in bad/bad.go:4: the package "other" doesn't match the output directory "bad"`,
+	}
+	if strings.Join(warnings, "\n") != strings.Join(wantWarnings, "\n") {
+		t.Errorf("have warnings\n%s\nwant\n%s", strings.Join(warnings, "\n"), strings.Join(wantWarnings, "\n"))
+	}
+	if len(proposals) != 3 {
+		return
+	}
+	if p := proposals[0]; p.Config.Inputs[0].NameSpace != "static" || strings.Join(p.Config.IgnoreGlob, " ") != ".* *.swp *~ *.map" {
+		t.Errorf("bad detected config %+v", p.Config)
+	}
+	if p := proposals[1]; !p.Config.Hybrid {
+		t.Errorf("outlined not hybrid")
+	}
+	if c := proposals[2].Config; c.Output != "gen/bindata.go" || c.Prefix != "templates" || !c.Inputs[0].Recursive {
+		t.Errorf("bad go:generate config %+v", c)
+	}
+
+	data := MarshalScanProposals(proposals)
+	if err = CheckConfig("xb.yaml", data); err != nil {
+		t.Errorf("bad marshaled config: %v\n%s", err, data)
+	}
+	for _, s := range []string{
+		"embedded:\n  # detected directories (3 files, 11 B)\n  - pkg: assets\n    fs: true\n",
+		"outlined:\n  # detected directories (1 files, 200 B)\n  - pkg: ui/xbassets\n    fs: true\n    hybrid: true\n",
+		"      - path: templates\n        recursive: true\n",
+	} {
+		if !strings.Contains(string(data), s) {
+			t.Errorf("%q not found in\n%s", s, data)
+		}
+	}
+}
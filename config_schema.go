@@ -0,0 +1,120 @@
+package xbindata
+
+import (
+	"reflect"
+	"strings"
+)
+
+//go:generate go run ./xb config schema -o xb.schema.json
+
+// ConfigSchemaID is the JSON Schema id of config file.
+const ConfigSchemaID = "https://raw.githubusercontent.com/moisespsena-go/xbindata/master/xb.schema.json"
+
+// configAliaser is implemented by the config types with key aliases.
+type configAliaser interface {
+	// configAliases returns the key of each alias.
+	configAliases() map[string]string
+}
+
+func (ManyConfigCommonDefaultInput) configAliases() map[string]string {
+	return map[string]string{"ns": "name_space"}
+}
+
+// configField is a config struct field.
+type configField struct {
+	Key  string
+	Type reflect.Type
+}
+
+// configFields returns the fields of config struct type, indexed by the
+// lower case keys. The embedded struct fields are squashed, as in the
+// UnmarshalMap implementations.
+func configFields(t reflect.Type) (fields map[string]configField) {
+	fields = map[string]configField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			for key, field := range configFields(f.Type) {
+				fields[key] = field
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		key := strings.Split(f.Tag.Get("mapstructure"), ",")[0]
+		if key == "-" {
+			continue
+		} else if key == "" {
+			key = strings.ToLower(f.Name)
+		}
+		fields[strings.ToLower(key)] = configField{key, f.Type}
+	}
+
+	if a, ok := reflect.Zero(t).Interface().(configAliaser); ok {
+		for alias, key := range a.configAliases() {
+			if field, ok := fields[key]; ok {
+				fields[alias] = configField{alias, field.Type}
+			}
+		}
+	}
+	return
+}
+
+// ConfigSchema returns the JSON Schema of config file (ManyConfig).
+func ConfigSchema() map[string]interface{} {
+	var (
+		defs   = map[string]interface{}{}
+		schema = configStructSchema(reflect.TypeOf(ManyConfig{}), defs)
+	)
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["$id"] = ConfigSchemaID
+	schema["title"] = "xbindata config file"
+	schema["definitions"] = defs
+	return schema
+}
+
+func configTypeSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return configTypeSchema(t.Elem(), defs)
+	case reflect.Struct:
+		name := t.Name()
+		if _, ok := defs[name]; !ok {
+			defs[name] = nil // recursion guard
+			defs[name] = configStructSchema(t, defs)
+		}
+		return map[string]interface{}{"$ref": "#/definitions/" + name}
+	case reflect.Slice, reflect.Array:
+		schema := map[string]interface{}{"type": "array", "items": configTypeSchema(t.Elem(), defs)}
+		if t.Implements(configScalarSliceType) {
+			return map[string]interface{}{"oneOf": []interface{}{configTypeSchema(t.Elem(), defs), schema}}
+		}
+		return schema
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": configTypeSchema(t.Elem(), defs)}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{}
+}
+
+func configStructSchema(t reflect.Type, defs map[string]interface{}) map[string]interface{} {
+	props := map[string]interface{}{}
+	for _, field := range configFields(t) {
+		props[field.Key] = configTypeSchema(field.Type, defs)
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           props,
+		"additionalProperties": false,
+	}
+}
@@ -12,8 +12,25 @@ type configContextKeyType uint8
 const (
 	ContextEnvKey configContextKeyType = iota
 	ContextInputKey
+	ContextProfileKey
 )
 
+// ContextWithProfile returns a copy of ctx with the selected config profile.
+func ContextWithProfile(ctx context.Context, profile string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ContextProfileKey, profile)
+}
+
+// ContextProfile returns the selected config profile of ctx.
+func ContextProfile(ctx context.Context) (profile string) {
+	if ctx != nil {
+		profile, _ = ctx.Value(ContextProfileKey).(string)
+	}
+	return
+}
+
 func ContextWithEnv(ctx context.Context, env map[string]string, noInherits ...bool) context.Context {
 	if ctx == nil {
 		ctx = context.Background()
@@ -63,7 +80,7 @@ func InputKey(ctx context.Context, format ...string) (key string) {
 func Env(update ...map[string]string) map[string]string {
 	items := make(map[string]string)
 	for _, item := range os.Environ() {
-		kv := strings.Split(item, "=")
+		kv := strings.SplitN(item, "=", 2)
 		items[kv[0]] = kv[1]
 	}
 	for _, env := range update {
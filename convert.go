@@ -6,7 +6,11 @@ package xbindata
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path"
@@ -15,6 +19,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	"github.com/moisespsena-go/xbindata/outlined"
@@ -36,6 +41,30 @@ type tocRegister struct {
 	toc    []Asset
 	byName map[string]int
 	mu     sync.Mutex
+
+	// collisions are the source paths of names registered by many
+	// non-override inputs, or by many override inputs.
+	collisions map[string][]string
+
+	dirs       []Asset
+	dirsByName map[string]int
+}
+
+// AppendDir registers the directory entries. See Config.PreserveDirs.
+func (t *tocRegister) AppendDir(dir ...Asset) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.dirsByName == nil {
+		t.dirsByName = map[string]int{}
+	}
+	for _, dir := range dir {
+		if i, ok := t.dirsByName[dir.Name]; ok {
+			t.dirs[i] = dir
+		} else {
+			t.dirsByName[dir.Name] = len(t.dirs)
+			t.dirs = append(t.dirs, dir)
+		}
+	}
 }
 
 func (t *tocRegister) Append(asset ...Asset) {
@@ -43,7 +72,21 @@ func (t *tocRegister) Append(asset ...Asset) {
 	defer t.mu.Unlock()
 	for _, asset := range asset {
 		if i, ok := t.byName[asset.Name]; ok {
-			t.toc[i] = asset
+			old := t.toc[i]
+			switch {
+			case asset.override && !old.override:
+				t.toc[i] = asset
+			case old.override && !asset.override:
+			default:
+				if t.collisions == nil {
+					t.collisions = map[string][]string{}
+				}
+				if _, ok := t.collisions[asset.Name]; !ok {
+					t.collisions[asset.Name] = []string{old.Path}
+				}
+				t.collisions[asset.Name] = append(t.collisions[asset.Name], asset.Path)
+				t.toc[i] = asset
+			}
 		} else {
 			t.byName[asset.Name] = len(t.toc)
 			t.toc = append(t.toc, asset)
@@ -55,16 +98,43 @@ func (t *tocRegister) Append(asset ...Asset) {
 // to Go code and writes new files to the output specified
 // in the given configuration.
 func Translate(c *Config) (count int, err error) {
+	var result *BuildResult
+	if result, err = TranslateResult(c); err != nil {
+		return
+	}
+	return result.Count, nil
+}
+
+// TranslateResult is like Translate, but returns the structured build result.
+func TranslateResult(c *Config) (result *BuildResult, err error) {
+	return translate(context.Background(), c)
+}
+
+// translate builds the assets of config. The ctx cancels the walking and
+// compressing of assets.
+func translate(ctx context.Context, c *Config) (result *BuildResult, err error) {
+	result = &BuildResult{StartedAt: time.Now()}
+	c.ctx = ctx
+	defer func() {
+		if err != nil {
+			result = nil
+		} else {
+			result.Duration = time.Since(result.StartedAt)
+		}
+	}()
 
 	// Ensure our configuration has sane values.
 	if err = c.validate(); err != nil {
 		return
 	}
 
+	result.Package, result.Outlined = c.Package, c.Outlined
+
 	var (
 		knownFuncs   = make(map[string]int)
 		visitedPaths = make(map[string]bool)
 		toc          []Asset
+		dirs         []Asset
 	)
 
 	{
@@ -82,23 +152,75 @@ func Translate(c *Config) (count int, err error) {
 				visitedPaths: visitedPaths,
 				mu:           &finderMu,
 				production:   c.InputProduction,
+				sidecars:     c.MetadataSidecars,
+				ctx:          ctx,
+			}
+
+			input.dirs = c.PreserveDirs
+			if input.Profile == "" {
+				input.Profile = c.Profile
 			}
 
 			prefix := c.Prefix
 			if input.Prefix != "" {
 				prefix = input.Prefix
 			}
+			if c.Dir != "" && !filepath.IsAbs(prefix) {
+				prefix = filepath.Join(c.Dir, prefix)
+			}
 
 			if err = finder.find(&input, path.Clean(prefix)); err != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
 				return
 			}
 		}
 
-		toc = tocr.toc
+		if err = c.Collisions.Check(tocr); err != nil {
+			return
+		}
+
+		toc, dirs = tocr.toc, tocr.dirs
 
 		sort.Slice(toc, func(i, j int) bool {
 			return toc[i].Name < toc[j].Name
 		})
+		sort.Slice(dirs, func(i, j int) bool {
+			return dirs[i].Name < dirs[j].Name
+		})
+	}
+
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	if c.Budget != nil {
+		if err = c.Budget.Check(c.Package, toc); err != nil {
+			if !c.BudgetWarnOnly {
+				return
+			}
+			log.Printf("WARNING: %v", err)
+			err = nil
+		}
+	}
+
+	if c.HashedNames != "" {
+		var manifest string
+		if toc, manifest, err = hashNames(c, toc, knownFuncs); err != nil {
+			return
+		}
+		result.addOutput(manifest)
+
+		sort.Slice(toc, func(i, j int) bool {
+			return toc[i].Name < toc[j].Name
+		})
+	}
+
+	if c.ContentTypes || c.MetadataSidecars || len(c.Metadata) > 0 {
+		if err = setMetadata(c, toc); err != nil {
+			return
+		}
 	}
 
 	// Create output file.
@@ -108,9 +230,12 @@ func Translate(c *Config) (count int, err error) {
 		return
 	}
 
-	var wd string
+	var (
+		wd, archive string
+		shards      []string
+	)
 
-	if wd, err = os.Getwd(); err != nil {
+	if wd, err = c.workDir(); err != nil {
 		return
 	}
 
@@ -173,21 +298,40 @@ func Translate(c *Config) (count int, err error) {
 		}
 		err = writeDebug(buf, c, toc)
 	} else {
-		err = writeRelease(buf, c, toc)
+		shards, err = writeRelease(buf, c, toc)
+		result.addOutput(shards...)
+	}
+	if err == nil && !c.Outlined && c.Output != OutputToStdout {
+		// the shards of previous generation
+		err = removeShards(c, len(shards))
 	}
 	if err != nil {
 		return
 	}
 
+	if c.HashedNames != "" {
+		if err = writeHashedNames(buf, toc); err != nil {
+			return
+		}
+	}
+
+	if c.Accessors != "" {
+		if err = writeAccessors(buf, c, toc, knownFuncs); err != nil {
+			return
+		}
+	}
+
 	if c.Hybrid {
-		if err = localFs(c); err != nil {
+		var devFile string
+		if devFile, err = localFs(c); err != nil {
 			return
 		}
+		result.addOutput(devFile)
 	}
 
 	if !c.Outlined {
 		// Write table of contents
-		if err = writeTOC(c, buf, toc); err != nil {
+		if err = writeTOC(c, buf, toc, dirs); err != nil {
 			return
 		}
 	}
@@ -198,21 +342,23 @@ func Translate(c *Config) (count int, err error) {
 			dest = c.OutlinedApi
 		}
 
-		if err = safefileWriteFile(dest, buf.Bytes(), 0); err != nil {
+		if err = c.writeFile(dest, buf.Bytes()); err != nil {
 			return
 		}
+		result.addOutput(dest)
 	}
 
 	if c.Outlined {
 		buf.Reset()
 
 		if !c.OulinedSkipApi {
-			if err = outlinedHeadersWrite(buf, toc, c); err == nil && c.OutlinedHeadersOutput != "" {
+			if err = outlinedHeadersWrite(buf, append(toc[:len(toc):len(toc)], dirs...), c); err == nil && c.OutlinedHeadersOutput != "" {
 				log.Printf("user headers file: `%v`\n", c.OutlinedHeadersOutput)
 
-				if err = safefileWriteFile(c.OutlinedHeadersOutput, buf.Bytes(), 0); err != nil {
+				if err = c.writeFile(c.OutlinedHeadersOutput, buf.Bytes()); err != nil {
 					return
 				}
+				result.addOutput(c.OutlinedHeadersOutput)
 			} else if err != nil {
 				err = fmt.Errorf("write headers to buffer failed: %v", err.Error())
 				return
@@ -228,38 +374,120 @@ func Translate(c *Config) (count int, err error) {
 		}
 
 		if !c.OutlinedProgram || (c.OutputWriter != nil || c.Output != OutputToProgram) {
-			headers := make(outlined.Headers, len(toc))
+			if err = ctx.Err(); err != nil {
+				return
+			}
+			var (
+				entries = append(toc[:len(toc):len(toc)], dirs...)
+				headers = make(outlined.Headers, len(entries))
+			)
 
-			for i, asset := range toc {
+			for i := range entries {
+				asset := &entries[i]
 				info, _ := asset.Info()
-				rpth, err := filepath.Rel(wd, asset.Path)
-				if err != nil {
-					rpth = asset.Path
+				fi := xbcommon.NewFileInfo(asset.Name, info.Size(), info.Mode(), info.ModTime(), asset.ctime)
+				if asset.link != "" {
+					fi.SetLink(asset.link)
+				}
+				if len(asset.metadata) > 0 {
+					fi.SetMetadata(asset.metadata)
+				}
+				headers[i] = outlined.NewHeader(fi, asset.Path)
+				if asset.data != nil {
+					headers[i].Opener = asset.Open
 				}
-				headers[i] = outlined.NewHeader(xbcommon.NewFileInfo(asset.Name, info.Size(), info.Mode(), info.ModTime(), asset.ctime), rpth)
 			}
 
 			if c.OutlinedProgram && c.OutputWriter != nil {
 				err = headers.AppendW(c.OutputWriter)
 			} else {
-				outputFile := c.Output
-				if outputFile, err = filepath.Abs(outputFile); err != nil {
-					err = fmt.Errorf("abs path failed: %v", err.Error())
-					return
+				outputFile := c.outputPath(c.Output)
+				if !filepath.IsAbs(outputFile) {
+					outputFile = filepath.Join(wd, outputFile)
 				}
 				log.Println("destination file: `" + outputFile + "`")
-				if c.OutlinedProgram {
-					err = headers.Append(outputFile)
-				} else if c.NoCompress {
-					err = headers.StoreFile(outputFile)
-				} else {
-					err = headers.StoreFileGz(outputFile)
+				if c.outlinedCompressed() {
+					outputFile += ".gz"
 				}
+				var d [sha256.Size]byte
+				d, err = writeOutlined(c, headers, outputFile)
+				result.addOutput(outputFile)
+				archive = outputFile
+
+				if err == nil && c.Checksums && !c.OutlinedProgram {
+					var sums string
+					if sums, err = writeArchiveChecksum(c, outputFile, d); err == nil {
+						result.addOutput(sums)
+					}
+				}
+			}
+			if err != nil {
+				return
 			}
 		}
 	}
 
-	return len(toc), nil
+	if c.Checksums {
+		var sums string
+		if sums, err = writeTreeChecksums(c, toc); err != nil {
+			return
+		}
+		result.addOutput(sums)
+	}
+
+	if c.SelfTest {
+		var pths []string
+		if pths, err = writeSelfTest(c, toc, dirs, archive); err != nil {
+			return
+		}
+		result.addOutput(pths...)
+	}
+
+	if err = result.setAssets(c, toc); err != nil {
+		return
+	}
+	if archive != "" && c.outlinedCompressed() {
+		var info os.FileInfo
+		if info, err = c.statFile(archive); err != nil {
+			return
+		}
+		result.TotalStoredSize = info.Size()
+	}
+	return result, nil
+}
+
+// writeOutlined writes the outlined archive of headers into pth, appending
+// it if is the program file, and returns the digest of written contents.
+func writeOutlined(c *Config, headers outlined.Headers, pth string) (d [sha256.Size]byte, err error) {
+	var f io.WriteCloser
+	if c.OutlinedProgram {
+		f, err = c.appendFile(pth)
+	} else {
+		f, err = c.createFile(pth)
+	}
+	if err != nil {
+		return
+	}
+
+	var (
+		h = sha256.New()
+		w = io.MultiWriter(f, h)
+	)
+	if c.OutlinedProgram {
+		err = headers.AppendW(w)
+	} else if c.NoCompress {
+		err = headers.Store(w)
+	} else {
+		gz := gzip.NewWriter(w)
+		if err = headers.Store(gz); err == nil {
+			err = gz.Close()
+		}
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	copy(d[:], h.Sum(nil))
+	return
 }
 
 var regFuncName = regexp.MustCompile(`[^a-zA-Z0-9_]`)
@@ -293,12 +521,17 @@ func safeFunctionName(name string, knownFuncs map[string]int) string {
 		name = "_" + name
 	}
 
-	num, ok := knownFuncs[name]
+	return uniqueName(name, knownFuncs)
+}
+
+// uniqueName appends a number suffix to name if it exists in known names.
+func uniqueName(name string, known map[string]int) string {
+	num, ok := known[name]
 	if ok {
-		knownFuncs[name] = num + 1
+		known[name] = num + 1
 		name = fmt.Sprintf("%s%d", name, num)
 	} else {
-		knownFuncs[name] = 2
+		known[name] = 2
 	}
 
 	return name
@@ -1,6 +1,7 @@
 package xbindata
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path"
@@ -10,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/moisespsena-go/xbindata/walker"
+	"github.com/moisespsena-go/xbindata/xbcommon"
 
 	"github.com/gobwas/glob"
 )
@@ -25,6 +27,10 @@ type Finder struct {
 	visitedPaths map[string]bool
 	mu           *sync.Mutex
 	production   bool
+	// sidecars skips the metadata sidecar files.
+	sidecars bool
+	// ctx cancels the walking.
+	ctx context.Context
 }
 
 // find now
@@ -41,7 +47,17 @@ func (this Finder) find(input *InputConfig, prefix string) (err error) {
 	}
 
 	return input.Walk(&this.visitedPaths, this.production, func(info walker.FileInfo) (err error) {
-		if info.IsDir() {
+		if err = this.ctx.Err(); err != nil {
+			return
+		}
+		if info.IsDir() && !input.dirs {
+			return nil
+		}
+		src := info.Path
+		if info.RealPath != "" {
+			src = info.RealPath
+		}
+		if this.sidecars && !info.IsDir() && isMetadataSidecar(src) {
 			return nil
 		}
 		for _, re := range this.ignore {
@@ -67,6 +83,10 @@ func (this Finder) find(input *InputConfig, prefix string) (err error) {
 			asset.Name = asset.Name[1:]
 		}
 
+		if info.AssetName != "" {
+			asset.Name = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(info.AssetName)), "/")
+		}
+
 		if len(info.NamePrefix) > 0 {
 			var namePrefix []string
 			for i := 0; i < input.DirReplacesCount; i++ {
@@ -86,12 +106,29 @@ func (this Finder) find(input *InputConfig, prefix string) (err error) {
 			return fmt.Errorf("Invalid file: %v", asset.Path)
 		}
 
-		if asset.Path, err = filepath.Abs(info.Path); err != nil {
+		if asset.Path, err = filepath.Abs(src); err != nil {
 			return err
 		}
 
 		asset.info = info
+
+		if info.IsDir() {
+			asset.dir = true
+			this.toc.AppendDir(asset)
+			return nil
+		}
+
 		asset.Size = info.Size()
+		asset.override = input.Override
+		asset.metadata = info.Metadata
+
+		if info.Link != "" {
+			asset.link = filepath.ToSlash(info.Link)
+			asset.Size = 0
+			if _, err = xbcommon.LinkTarget(asset.Name, asset.link); err != nil {
+				return fmt.Errorf("%s: %v", info.Path, err)
+			}
+		}
 
 		this.mu.Lock()
 		defer this.mu.Unlock()
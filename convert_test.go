@@ -1,11 +1,36 @@
 package xbindata
 
 import (
+	"context"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 )
 
+// findFiles finds the assets of dir as Translate does.
+func findFiles(dir, prefix string, recursive bool, toc *[]Asset, ignore []*regexp.Regexp, knownFuncs map[string]int, visitedPaths map[string]bool) error {
+	tocr := &tocRegister{byName: map[string]int{}}
+	finder := Finder{
+		toc:          tocr,
+		ignore:       ignore,
+		knownFuncs:   knownFuncs,
+		visitedPaths: visitedPaths,
+		mu:           &sync.Mutex{},
+		ctx:          context.Background(),
+	}
+	if err := finder.find(&InputConfig{Path: dir, Recursive: recursive}, path.Clean(prefix)); err != nil {
+		return err
+	}
+	sort.Slice(tocr.toc, func(i, j int) bool {
+		return tocr.toc[i].Name < tocr.toc[j].Name
+	})
+	*toc = tocr.toc
+	return nil
+}
+
 func TestSafeFunctionName(t *testing.T) {
 	var knownFuncs = make(map[string]int)
 	name1 := safeFunctionName("foo/bar", knownFuncs)
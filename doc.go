@@ -11,6 +11,14 @@ The following paragraphs cover some of the customization options
 which can be specified in the Config struct, which must be passed into
 the Translate() call.
 
+The NewBuilder() call builds the Config from the With* options, reports
+the incompatible options up front and builds with a context.Context, which
+cancels the walking and compressing of assets.
+
+The generated files are written into the Config.OutputFS, resolving the
+relative paths from Config.Dir. Use NewMemOutputFS() to generate into
+memory, without touching the working directory.
+
 
 Debug vs Release builds
 
@@ -32,6 +40,12 @@ refresh in the browser to see those changes. Embedding the assets with the
 ready for deployment, just re-invoke `xbindata` without the `-debug` flag.
 It will now embed the latest version of the assets.
 
+The `dev` tagged file system of `Hybrid` builds watches the local files and
+publishes the add, modify and remove events to the xbfs.FileSystem
+subscribers. The xbfs.FileSystem.EventsHandler() streams them as
+Server-Sent Events, for the browser live reload. Set XB_NO_WATCH=true to
+disable the watching.
+
 
 Lower memory footprint
 
@@ -80,6 +94,32 @@ generating a runtime error.
 	}
 
 
+Data cache
+
+Each Data() call of compressed assets decompresses the whole asset again.
+The xbcommon.DataCache is a bounded LRU cache of the decompressed data, with
+a byte budget and hit and miss statistics. Set it with Assets.SetCache() or
+xbfs.FileSystem.SetCache(), and warm up the hot assets with Preload():
+
+	cache := xbcommon.NewDataCache(16 << 20)
+	fs := assets.FS().(*xbfs.FileSystem).SetCache(cache)
+	fs.Preload("**.html", "templates/**")
+
+The cached data are shared, so the data returned by Data() must not be
+modified.
+
+
+Compiler memory
+
+The Go compiler holds the whole package in memory, so large asset sets may
+exhaust the memory of build machines. The ShardSize option writes the assets
+data into the `<output base>_shard_NNN` packages of about ShardSize bytes of
+Go source, compiled one apart from the other. The output file keeps the API,
+the table of contents and the tree, and imports the shard packages by the
+import path of output directory, resolved from the go.mod file or from the
+Package import path.
+
+
 Optional compression
 
 The NoCompress option indicates that the supplied assets are *not* GZIP
@@ -3,7 +3,6 @@ package xbindata
 import (
 	"fmt"
 	"io"
-	"os"
 	"path/filepath"
 )
 
@@ -36,14 +35,14 @@ func main() {
 
 var headers = outlined.Headers{
 `
-	cwd, _ := os.Getwd()
+	cwd, _ := c.workDir()
 	for _, asset := range toc {
 		info, _ := asset.InfoExport(c)
 		rpth, err := filepath.Rel(cwd, asset.Path)
 		if err != nil {
 			rpth = asset.Path
 		}
-		data += fmt.Sprintf("\toutlined.NewHeader(bc.NewFileInfo(%q, %s), %q),\n", asset.Name, info, rpth)
+		data += fmt.Sprintf("\toutlined.NewHeader(bc.NewFileInfo(%q, %s)%s, %q),\n", asset.Name, info, asset.infoSetters(), rpth)
 	}
 
 	data += "}\n"
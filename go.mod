@@ -1,11 +1,12 @@
 module github.com/moisespsena-go/xbindata
 
-go 1.14
+go 1.16
 
 require (
 	github.com/apex/log v1.1.4
 	github.com/djherbis/times v1.2.0
 	github.com/dustin/go-humanize v1.0.0
+	github.com/fsnotify/fsnotify v1.4.7
 	github.com/go-errors/errors v1.0.2
 	github.com/gobwas/glob v0.2.3
 	github.com/mitchellh/mapstructure v1.3.0
@@ -24,6 +25,8 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/cobra v1.0.0
 	github.com/spf13/viper v1.7.0
+	golang.org/x/text v0.3.2
 	gopkg.in/djherbis/times.v1 v1.2.0 // indirect
 	gopkg.in/yaml.v2 v2.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,278 @@
+package xbindata
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	path_helpers "github.com/moisespsena-go/path-helpers"
+	"github.com/pkg/errors"
+)
+
+// GoPathPrefix is the input path prefix of Go import paths.
+const GoPathPrefix = "go:"
+
+// goModule is the `go list -m -json` or `go mod download -json` output.
+type goModule struct {
+	Path    string
+	Version string
+	Main    bool
+	Dir     string
+	GoMod   string
+	Replace *goModule
+	Error   interface{}
+}
+
+// goBuildListCache is the cached build list of main module, with the
+// go.mod and go.sum content hash.
+type goBuildListCache struct {
+	sum  [sha256.Size]byte
+	mods []goModule
+}
+
+// goModules caches the build list of module, by go.mod file of main module.
+// The build list is loaded again if the go.mod or go.sum content changes.
+var goModules = struct {
+	sync.Mutex
+	byGoMod map[string]goBuildListCache
+}{byGoMod: map[string]goBuildListCache{}}
+
+// goCmd runs the go command and returns the stdout.
+var goCmd = func(args ...string) (out []byte, err error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Stderr = &stderr
+	if out, err = cmd.Output(); err != nil {
+		err = fmt.Errorf("go %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return
+}
+
+// ResolveGoPath resolves the `go:` input path spec (without prefix) to the
+// import path and the local directory, using module semantics. The spec is
+// an import path, optionally with the module version, like
+// `example.com/ui/assets` or `example.com/ui@v1.2.3/assets`.
+//
+// Without version, the module is looked up in the build list of current
+// module (`go list -m all`), which follows the replace directives and the
+// go.work workspaces. If current directory isn't into module, the GOPATH is
+// used.
+//
+// With version, the module directory of GOMODCACHE is used, or the module is
+// downloaded. So both work offline against a pre-populated module cache.
+func ResolveGoPath(spec string) (pkg, dir string, err error) {
+	if at := strings.IndexByte(spec, '@'); at != -1 {
+		var (
+			mod     = spec[:at]
+			version = spec[at+1:]
+			sub     string
+		)
+		if pos := strings.IndexByte(version, '/'); pos != -1 {
+			version, sub = version[:pos], version[pos+1:]
+		}
+		if mod == "" || version == "" {
+			return "", "", fmt.Errorf("bad go path %q", spec)
+		}
+		pkg = strings.TrimSuffix(mod+"/"+sub, "/")
+		if dir, err = goModuleVersionDir(mod, version); err != nil {
+			return
+		}
+		return pkg, filepath.Join(dir, filepath.FromSlash(sub)), nil
+	}
+
+	pkg = spec
+
+	var (
+		mods  []goModule
+		gomod string
+	)
+	if mods, gomod, err = goBuildList(); err != nil {
+		return
+	}
+
+	if len(mods) == 0 {
+		_, dir = path_helpers.ResolveGoSrcPath(pkg)
+		return
+	}
+
+	var mod *goModule
+	for i, m := range mods {
+		if (pkg == m.Path || strings.HasPrefix(pkg, m.Path+"/")) && (mod == nil || len(m.Path) > len(mod.Path)) {
+			mod = &mods[i]
+		}
+	}
+	if mod == nil {
+		return "", "", fmt.Errorf("no module of current build list provides %q", pkg)
+	}
+
+	sub := strings.TrimPrefix(strings.TrimPrefix(pkg, mod.Path), "/")
+
+	switch {
+	case mod.Replace != nil && mod.Replace.Version == "":
+		// replaced by local directory, relative to the main module
+		switch {
+		case mod.Replace.Dir != "":
+			dir = mod.Replace.Dir
+		case mod.Replace.GoMod != "":
+			dir = filepath.Dir(mod.Replace.GoMod)
+		case filepath.IsAbs(mod.Replace.Path):
+			dir = mod.Replace.Path
+		default:
+			dir = filepath.Join(filepath.Dir(gomod), filepath.FromSlash(mod.Replace.Path))
+		}
+	case mod.Replace != nil:
+		if dir = mod.Replace.Dir; dir == "" {
+			dir, err = goModuleVersionDir(mod.Replace.Path, mod.Replace.Version)
+		}
+	case mod.Dir != "":
+		dir = mod.Dir
+	default:
+		dir, err = goModuleVersionDir(mod.Path, mod.Version)
+	}
+	if err != nil {
+		return
+	}
+	return pkg, filepath.Join(dir, filepath.FromSlash(sub)), nil
+}
+
+// goBuildList returns the build list of current module and its go.mod file.
+// If current directory isn't into module, returns nil.
+func goBuildList() (mods []goModule, gomod string, err error) {
+	var out []byte
+	if out, err = goCmd("env", "GOMOD"); err != nil {
+		return
+	}
+	if gomod = strings.TrimSpace(string(out)); gomod == "" || gomod == os.DevNull {
+		return nil, "", nil
+	}
+
+	goModules.Lock()
+	defer goModules.Unlock()
+
+	sum := goModSum(gomod)
+	if cache, ok := goModules.byGoMod[gomod]; ok && cache.sum == sum {
+		return cache.mods, gomod, nil
+	}
+
+	if out, err = goCmd("list", "-m", "-json", "all"); err != nil {
+		return
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var m goModule
+		if err = dec.Decode(&m); err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			return nil, "", errors.Wrap(err, "decode go modules")
+		}
+		mods = append(mods, m)
+	}
+
+	goModules.byGoMod[gomod] = goBuildListCache{sum, mods}
+	return
+}
+
+// goModSum returns the content hash of go.mod file and its go.sum file. The
+// missing files are hashed as empty.
+func goModSum(gomod string) (sum [sha256.Size]byte) {
+	h := sha256.New()
+	for _, pth := range []string{gomod, strings.TrimSuffix(gomod, ".mod") + ".sum"} {
+		data, _ := ioutil.ReadFile(pth)
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	copy(sum[:], h.Sum(nil))
+	return
+}
+
+// goModuleVersionDir returns the directory of module version into
+// GOMODCACHE. If it does not exists, downloads the module.
+func goModuleVersionDir(mod, version string) (dir string, err error) {
+	var (
+		out        []byte
+		cache      string
+		escMod     = goEscapePath(mod)
+		escVersion = goEscapePath(version)
+	)
+	if out, err = goCmd("env", "GOMODCACHE"); err != nil {
+		return
+	}
+	if cache = strings.TrimSpace(string(out)); cache == "" {
+		if out, err = goCmd("env", "GOPATH"); err != nil {
+			return
+		}
+		cache = filepath.Join(filepath.SplitList(strings.TrimSpace(string(out)))[0], "pkg", "mod")
+	}
+
+	dir = filepath.Join(cache, filepath.FromSlash(escMod)+"@"+escVersion)
+	if _, err = os.Stat(dir); err == nil {
+		return
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	var m goModule
+	if out, err = goCmd("mod", "download", "-json", mod+"@"+version); err != nil {
+		if len(out) == 0 || json.Unmarshal(out, &m) != nil || m.Error == nil {
+			return "", errors.Wrapf(err, "download module %s@%s", mod, version)
+		}
+		return "", fmt.Errorf("download module %s@%s: %v", mod, version, m.Error)
+	}
+	if err = json.Unmarshal(out, &m); err != nil {
+		return "", errors.Wrap(err, "decode go module")
+	}
+	return m.Dir, nil
+}
+
+// goEscapePath escapes the module path or version as in the module cache:
+// each upper case letter is replaced by `!` followed by lower case letter.
+func goEscapePath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// goImportPath returns the import path of local directory, from the module
+// path of the go.mod file of dir or of its parents. The directory may not
+// exist yet.
+func goImportPath(dir string) (pkg string, err error) {
+	if dir, err = filepath.Abs(dir); err != nil {
+		return
+	}
+	for mod := dir; ; {
+		var data []byte
+		if data, err = ioutil.ReadFile(filepath.Join(mod, "go.mod")); err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+					rel, _ := filepath.Rel(mod, dir)
+					return path.Join(strings.Trim(fields[1], `"`), filepath.ToSlash(rel)), nil
+				}
+			}
+			return "", fmt.Errorf("no module path in %q", filepath.Join(mod, "go.mod"))
+		} else if !os.IsNotExist(err) {
+			return
+		}
+		parent := filepath.Dir(mod)
+		if parent == mod {
+			return "", fmt.Errorf("no go.mod file in %q or its parents", dir)
+		}
+		mod = parent
+	}
+}
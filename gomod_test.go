@@ -0,0 +1,106 @@
+package xbindata
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveGoPath(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "xbgomod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	var (
+		gomod    = filepath.Join(tmp, "work", "m", "go.mod")
+		modCache = filepath.Join(tmp, "modcache")
+		cached   = filepath.Join(modCache, "example.com", "!cached@v1.0.0")
+		lists    int
+	)
+	if err = os.MkdirAll(cached, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(cmd func(args ...string) ([]byte, error)) { goCmd = cmd }(goCmd)
+	goCmd = func(args ...string) ([]byte, error) {
+		switch strings.Join(args, " ") {
+		case "env GOMOD":
+			return []byte(gomod + "\n"), nil
+		case "env GOMODCACHE":
+			return []byte(modCache + "\n"), nil
+		case "list -m -json all":
+			lists++
+			return []byte(`{"Path": "example.com/m", "Main": true, "Dir": "` + filepath.Dir(gomod) + `", "GoMod": "` + gomod + `"}
+{"Path": "example.com/local", "Version": "v0.0.0", "Replace": {"Path": "../local"}}
+{"Path": "example.com/local/v2", "Version": "v2.0.0", "Replace": {"Path": "../local2", "GoMod": "/abs/local2/go.mod"}}
+{"Path": "example.com/dep", "Version": "v1.1.0", "Dir": "/cache/dep@v1.1.0"}
+{"Path": "example.com/old", "Version": "v1.0.0", "Replace": {"Path": "example.com/new", "Version": "v1.2.0", "Dir": "/cache/new@v1.2.0"}}
+`), nil
+		case "mod download -json example.com/pinned@v1.2.3":
+			return []byte(`{"Path": "example.com/pinned", "Version": "v1.2.3", "Dir": "/cache/pinned@v1.2.3"}`), nil
+		case "mod download -json example.com/missing@v1.0.0":
+			return []byte(`{"Path": "example.com/missing", "Version": "v1.0.0", "Error": "not found"}`), fmt.Errorf("exit status 1")
+		}
+		return nil, fmt.Errorf("unexpected go %s", strings.Join(args, " "))
+	}
+
+	for _, tt := range []struct {
+		spec, pkg, dir, err string
+	}{
+		{spec: "example.com/m/assets", pkg: "example.com/m/assets", dir: filepath.Join(tmp, "work", "m", "assets")},
+		{spec: "example.com/local/ui", pkg: "example.com/local/ui", dir: filepath.Join(tmp, "work", "local", "ui")},
+		{spec: "example.com/local/v2/ui", pkg: "example.com/local/v2/ui", dir: filepath.FromSlash("/abs/local2/ui")},
+		{spec: "example.com/dep/static", pkg: "example.com/dep/static", dir: filepath.FromSlash("/cache/dep@v1.1.0/static")},
+		{spec: "example.com/old", pkg: "example.com/old", dir: filepath.FromSlash("/cache/new@v1.2.0")},
+		{spec: "example.com/Cached@v1.0.0/ui", pkg: "example.com/Cached/ui", dir: filepath.Join(cached, "ui")},
+		{spec: "example.com/pinned@v1.2.3/ui", pkg: "example.com/pinned/ui", dir: filepath.FromSlash("/cache/pinned@v1.2.3/ui")},
+		{spec: "example.com/missing@v1.0.0", err: "download module example.com/missing@v1.0.0: not found"},
+		{spec: "example.com/unknown/ui", err: `no module of current build list provides "example.com/unknown/ui"`},
+		{spec: "example.com/m@", err: "bad go path"},
+	} {
+		pkg, dir, err := ResolveGoPath(tt.spec)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: have error %v, want %q", tt.spec, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.spec, err)
+		} else if pkg != tt.pkg || dir != tt.dir {
+			t.Errorf("%s: have %q %q, want %q %q", tt.spec, pkg, dir, tt.pkg, tt.dir)
+		}
+	}
+	if lists != 1 {
+		t.Errorf("build list loaded %d times, want 1", lists)
+	}
+
+	// other main module
+	gomod = filepath.Join(tmp, "work", "n", "go.mod")
+	if _, dir, err := ResolveGoPath("example.com/local"); err != nil || dir != filepath.Join(tmp, "work", "local") || lists != 2 {
+		t.Errorf("other module: have %q %v, %d loads", dir, err, lists)
+	}
+
+	// changed go.mod and go.sum
+	if err = os.MkdirAll(filepath.Dir(gomod), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"go.mod", "go.sum", "go.sum"} {
+		if err = ioutil.WriteFile(filepath.Join(filepath.Dir(gomod), name), []byte(fmt.Sprint(i)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 2; j++ {
+			if _, _, err = ResolveGoPath("example.com/local"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if lists != 3+i {
+			t.Errorf("%s changed: build list loaded %d times, want %d", name, lists, 3+i)
+		}
+	}
+}
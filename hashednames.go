@@ -0,0 +1,138 @@
+package xbindata
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+// apiOutput returns the path of generated go file.
+func (c *Config) apiOutput() string {
+	if c.Outlined {
+		if c.OutlinedApi == "" {
+			return "assets.go"
+		}
+		return c.OutlinedApi
+	}
+	return c.Output
+}
+
+// hashNames sets the hashed name of each toc asset, writes the manifest
+// file and returns the toc with the manifest asset.
+func hashNames(c *Config, toc []Asset, knownFuncs map[string]int) (_ []Asset, manifestPth string, err error) {
+	var manifest = make(map[string]string, len(toc))
+
+	for i := range toc {
+		asset := &toc[i]
+		if asset.Name == c.HashedNamesManifest {
+			return nil, "", fmt.Errorf("asset %q (%s) conflicts with the hashed names manifest", asset.Name, asset.Path)
+		}
+		if asset.link != "" {
+			continue
+		}
+		digest, err := asset.Digest()
+		if err != nil {
+			return nil, "", err
+		}
+		asset.hashedName = xbcommon.HashedName(asset.Name, *digest, c.HashedNamesLength)
+		manifest[asset.Name] = asset.hashedName
+		if c.HashedNames == HashedNamesOnly {
+			asset.originalName, asset.Name = asset.Name, asset.hashedName
+		}
+	}
+
+	var (
+		api  = c.apiOutput()
+		dir  = filepath.Dir(api)
+		name = strings.TrimSuffix(filepath.Base(api), ".go") + "_manifest.json"
+		data []byte
+	)
+
+	if data, err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return
+	}
+
+	data = append(data, '\n')
+	manifestPth = filepath.Join(dir, name)
+	if err = c.writeFile(manifestPth, data); err != nil {
+		return
+	}
+	if err = gitIgnore(c, dir, name); err != nil {
+		return
+	}
+
+	// the manifest contents are kept in memory, the output file system may
+	// not be the OS file system
+	var (
+		now   = time.Now()
+		asset = Asset{Name: c.HashedNamesManifest, Size: int64(len(data)), data: data, ctime: now}
+		wd    string
+	)
+	if wd, err = c.workDir(); err != nil {
+		return
+	}
+	if asset.Path = c.outputPath(manifestPth); !filepath.IsAbs(asset.Path) {
+		asset.Path = filepath.Join(wd, asset.Path)
+	}
+	asset.info = xbcommon.NewFileInfo(asset.Path, asset.Size, 0644, now, now)
+	asset.Func = safeFunctionName(asset.Name, knownFuncs)
+	return append(toc, asset), manifestPth, nil
+}
+
+// OriginalName returns the name of asset before hashing.
+func (a *Asset) OriginalName() string {
+	if a.originalName != "" {
+		return a.originalName
+	}
+	return a.Name
+}
+
+// writeHashedNames writes the hashed names map and the HashedName and
+// Original functions.
+func writeHashedNames(w io.Writer, toc []Asset) (err error) {
+	if _, err = fmt.Fprint(w, "\nvar hashedNames = map[string]string{\n"); err != nil {
+		return
+	}
+	for i := range toc {
+		if toc[i].hashedName == "" {
+			continue
+		}
+		if _, err = fmt.Fprintf(w, "\t%q: %q,\n", toc[i].OriginalName(), toc[i].hashedName); err != nil {
+			return
+		}
+	}
+	_, err = fmt.Fprint(w, `}
+
+var originalNames = func() map[string]string {
+	names := make(map[string]string, len(hashedNames))
+	for original, hashed := range hashedNames {
+		names[hashed] = original
+	}
+	return names
+}()
+
+// HashedName returns the fingerprinted name of asset. If the asset does not
+// have a hashed name, returns name.
+func HashedName(name string) string {
+	if hashed, ok := hashedNames[name]; ok {
+		return hashed
+	}
+	return name
+}
+
+// Original returns the original name of the hashed asset name. If hashed is
+// not a hashed name, returns hashed.
+func Original(hashed string) string {
+	if original, ok := originalNames[hashed]; ok {
+		return original
+	}
+	return hashed
+}
+`)
+	return
+}
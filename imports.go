@@ -30,7 +30,7 @@ func write_imports(w io.Writer, c *Config, imports ...string) (err error) {
 	for _, imp := range imports {
 		if _, ok := importsMap[imp]; !ok {
 			if imp[0] == '-' {
-				excludes = append(excludes, imp[0:])
+				excludes = append(excludes, imp[1:])
 			} else {
 				importsMap[imp] = true
 			}
@@ -1,35 +1,23 @@
 package xbindata
 
 import (
-	"os"
+	"path"
 	"strings"
 )
 
 const tagDev = "dev"
 
-func localFs(c *Config) (err error) {
-	var (
-		f   *os.File
-		pth string
-	)
+func localFs(c *Config) (pth string, err error) {
 	if c.Outlined {
 		pth = strings.TrimSuffix(c.OutlinedApi, ".go") + "_dev.go"
 	}
 
-	if f, err = os.Create(pth); err != nil {
-		return
-	}
-
-	defer f.Close()
-
-	if _, err = f.WriteString("// +build " + tagDev + "\n\npackage " + c.Package + "\n"); err != nil {
-		return
-	}
-	var data string
+	var data = "// +build " + tagDev + "\n\npackage " + path.Base(c.Package) + "\n"
 
 	data += `
 import (
 	"bufio"
+	"log"
 	"os"
 	"path"
 	"strings"
@@ -38,7 +26,6 @@ import (
 	path_helpers "github.com/moisespsena-go/path-helpers"
 	"gopkg.in/yaml.v2"
 
-	"github.com/moisespsena-go/assetfs"
 	fsapi "github.com/moisespsena-go/assetfs/assetfsapi"
 
 	bc "github.com/moisespsena-go/xbindata/xbcommon"
@@ -50,6 +37,7 @@ var (
 	mu              sync.Mutex
 	fs              fsapi.Interface
 	fsLoadCallbacks []func(fs fsapi.Interface)
+	watcher         *xbfs.Watcher
 
 	__file__ = path_helpers.GetCalledFile(true)
 )
@@ -94,7 +82,7 @@ func Load() {
 	pathScanner.Scan()
 	nameScanner.Scan()
 
-	var assets []bc.Asset
+	var files = map[string]string{}
 
 	for pathScanner.Scan() && nameScanner.Scan() {
 		var (
@@ -105,17 +93,21 @@ func Load() {
 		)
 		yaml.Unmarshal([]byte(pth), &pathS)
 		yaml.Unmarshal([]byte(name), &nameS)
-		pth, name = path.Join(path.Dir(__file__), pathS[0]), nameS[0].Name
+		files[path.Join(path.Dir(__file__), pathS[0])] = nameS[0].Name
+	}
 
-		if info, err := os.Stat(pth); err != nil {
-			panic(err)
-		} else {
-			localAsset := assetfs.NewRealFileInfo(fsapi.OsFileInfoToBasic(name, info), pth)
-			assets = append(assets, &bc.LocalFile{RealFileInfo: localAsset})
-		}
+	tree, err := bc.NewLocalTree(files)
+	if err != nil {
+		panic(err)
 	}
+	fs = xbfs.NewFileSystem(tree.Root())
 
-	fs = xbfs.NewFileSystem(bc.NewTree(assets...).Root())
+	// publishes the local files changes, see xbfs.FileSystem.Subscribe
+	if os.Getenv("XB_NO_WATCH") != "true" {
+		if watcher, err = xbfs.Watch(fs.(*xbfs.FileSystem), files); err != nil {
+			log.Printf("WARNING: xbindata watch: %v", err)
+		}
+	}
 }
 
 func FS() fsapi.Interface {
@@ -124,7 +116,6 @@ func FS() fsapi.Interface {
 }
 `
 
-	_, err = f.WriteString(data)
-
+	err = c.writeFile(pth, []byte(data))
 	return
 }
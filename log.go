@@ -1,8 +1,8 @@
 package xbindata
 
 import (
+	"bytes"
 	"fmt"
-	"os"
 	"path/filepath"
 	"strconv"
 
@@ -10,14 +10,12 @@ import (
 )
 
 func logTocAndInputs(dir, base string, c *Config, toc []Asset) (err error) {
-	var wd string
-	if wd, err = os.Getwd(); err != nil {
+	var wd, absDir string
+	if wd, err = c.workDir(); err != nil {
 		return
 	}
-	if !filepath.IsAbs(dir) {
-		if dir, err = filepath.Abs(filepath.Join(wd, dir)); err != nil {
-			return
-		}
+	if absDir = dir; !filepath.IsAbs(absDir) {
+		absDir = filepath.Join(wd, absDir)
 	}
 
 	var ignores = []string{
@@ -25,94 +23,55 @@ func logTocAndInputs(dir, base string, c *Config, toc []Asset) (err error) {
 		base + "toc_paths.yml",
 	}
 
-	if err = gitIgnore(dir, ignores...); err != nil {
+	if err = gitIgnore(c, dir, ignores...); err != nil {
 		return err
 	}
 
-	func() {
-		var (
-			inputsPth = filepath.Join(dir, base+"inputs.yml")
-			f         *os.File
-		)
+	var inputs bytes.Buffer
+	inputs.WriteString("# " + generatedHeader)
 
-		if f, err = os.Create(inputsPth); err != nil {
-			return
-		}
-		defer f.Close()
+	tmpl := "- { path: %q, recursive: %v, ns: %q, prefix: %q }\n"
 
-		if _, err = f.WriteString("# " + generatedHeader); err != nil {
+	for _, input := range c.Input {
+		relative := input.Path
+		if !filepath.IsAbs(relative) {
+			relative = filepath.Join(wd, relative)
+		}
+		if relative, err = filepath.Rel(absDir, relative); err != nil {
 			return
 		}
 
-		tmpl := "- { path: %q, recursive: %v, ns: %q, prefix: %q }\n"
-
-		for _, input := range c.Input {
-			relative := input.Path
-			if !filepath.IsAbs(relative) {
-				relative = filepath.Join(wd, relative)
-			}
-			relative, err = filepath.Rel(dir, relative)
-			if err != nil {
-				return
-			}
-
-			prefix := input.Prefix
-			if !filepath.IsAbs(prefix) {
-				prefix = filepath.Join(wd, prefix)
-			}
-			prefix, err = filepath.Rel(dir, prefix)
-			if err != nil {
-				return
-			}
-
-			if _, err = fmt.Fprintf(f, tmpl, relative, input.Recursive, input.NameSpace, prefix); err != nil {
-				return
-			}
+		prefix := input.Prefix
+		if !filepath.IsAbs(prefix) {
+			prefix = filepath.Join(wd, prefix)
 		}
-	}()
-
-	if err != nil {
-		return err
-	}
-
-	func() {
-		var (
-			tocPth               = filepath.Join(dir, base+"toc_")
-			ptocNames            = tocPth + "names.yml"
-			ptocPaths            = tocPth + "paths.yml"
-			ftocNames, ftocPaths *os.File
-		)
-
-		if ftocNames, err = os.Create(ptocNames); err != nil {
+		if prefix, err = filepath.Rel(absDir, prefix); err != nil {
 			return
 		}
-		defer ftocNames.Close()
 
-		if ftocPaths, err = os.Create(ptocPaths); err != nil {
-			return
-		}
-		defer ftocPaths.Close()
+		fmt.Fprintf(&inputs, tmpl, relative, input.Recursive, input.NameSpace, prefix)
+	}
 
-		if _, err = ftocNames.WriteString("# " + generatedHeader); err != nil {
-			return
-		}
-		if _, err = ftocPaths.WriteString("# " + generatedHeader); err != nil {
-			return
-		}
+	if err = c.writeFile(filepath.Join(dir, base+"inputs.yml"), inputs.Bytes()); err != nil {
+		return
+	}
+
+	var tocNames, tocPaths bytes.Buffer
+	tocNames.WriteString("# " + generatedHeader)
+	tocPaths.WriteString("# " + generatedHeader)
 
-		for _, asset := range toc {
-			relative, err := filepath.Rel(dir, asset.Path)
-			if err != nil {
-				return
-			}
-			if _, err = fmt.Fprintf(ftocNames, "- { name: %q, size: %s }\n", asset.Name, bits2str.Bits(asset.Size)*bits2str.Byte); err != nil {
-				return
-			}
-			if _, err = ftocPaths.WriteString("- " + strconv.Quote(relative) + "\n"); err != nil {
-				return
-			}
+	for _, asset := range toc {
+		relative, err := filepath.Rel(absDir, asset.Path)
+		if err != nil {
+			return err
 		}
-	}()
+		fmt.Fprintf(&tocNames, "- { name: %q, size: %s }\n", asset.Name, bits2str.Bits(asset.Size)*bits2str.Byte)
+		tocPaths.WriteString("- " + strconv.Quote(relative) + "\n")
+	}
 
-	return
+	tocPth := filepath.Join(dir, base+"toc_")
+	if err = c.writeFile(tocPth+"names.yml", tocNames.Bytes()); err != nil {
+		return
+	}
+	return c.writeFile(tocPth+"paths.yml", tocPaths.Bytes())
 }
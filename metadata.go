@@ -0,0 +1,142 @@
+package xbindata
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"gopkg.in/yaml.v2"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+// MetadataSidecarSuffix is the suffix of asset metadata sidecar files,
+// example: `app.css.meta.yaml` holds the `app.css` metadata.
+const MetadataSidecarSuffix = ".meta.yaml"
+
+// MetadataRule sets the metadata Values of assets matching the Glob
+// pattern. The `*` does not matches the `/` separator, use `**` to
+// matches any sub directory.
+type MetadataRule struct {
+	Glob   string
+	Values map[string]string
+
+	glob glob.Glob
+}
+
+func (r *MetadataRule) compile() (err error) {
+	if r.glob, err = glob.Compile(r.Glob, '/'); err != nil {
+		err = fmt.Errorf("invalid metadata glob pattern %q: %v", r.Glob, err)
+	}
+	return
+}
+
+// isMetadataSidecar returns if pth is the metadata sidecar of a existing
+// file.
+func isMetadataSidecar(pth string) bool {
+	if !strings.HasSuffix(pth, MetadataSidecarSuffix) {
+		return false
+	}
+	info, err := os.Stat(strings.TrimSuffix(pth, MetadataSidecarSuffix))
+	return err == nil && !info.IsDir()
+}
+
+// detectContentType returns the content type from file extension, or
+// detected from the first 512 bytes of contents.
+func detectContentType(asset *Asset) (ct string, err error) {
+	if ct = mime.TypeByExtension(path.Ext(asset.Name)); ct != "" {
+		return
+	}
+	var r io.ReadCloser
+	if r, err = asset.Open(); err != nil {
+		return
+	}
+	defer r.Close()
+	var b = make([]byte, 512)
+	n, err := io.ReadFull(r, b)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(b[:n]), nil
+}
+
+// setMetadata sets the metadata of toc assets. The values of detected
+// content type, rules and sidecar files are merged in that order.
+func setMetadata(c *Config, toc []Asset) (err error) {
+	for i := range toc {
+		var (
+			asset    = &toc[i]
+			metadata = map[string]string{}
+		)
+		if asset.link != "" {
+			continue
+		}
+
+		if c.ContentTypes {
+			var ct string
+			if ct, err = detectContentType(asset); err != nil {
+				return fmt.Errorf("detect content type of %q: %v", asset.Path, err)
+			}
+			metadata[xbcommon.MetadataContentType] = ct
+		}
+
+		for _, rule := range c.Metadata {
+			if rule.glob.Match(asset.OriginalName()) {
+				for key, value := range rule.Values {
+					metadata[key] = value
+				}
+			}
+		}
+
+		// the walker entry values
+		for key, value := range asset.metadata {
+			metadata[key] = value
+		}
+
+		if c.MetadataSidecars {
+			var data []byte
+			if data, err = ioutil.ReadFile(asset.Path + MetadataSidecarSuffix); err == nil {
+				var values map[string]string
+				if err = yaml.Unmarshal(data, &values); err != nil {
+					return fmt.Errorf("parse metadata sidecar of %q: %v", asset.Path, err)
+				}
+				for key, value := range values {
+					metadata[key] = value
+				}
+			} else if !os.IsNotExist(err) {
+				return
+			}
+			err = nil
+		}
+
+		if len(metadata) > 0 {
+			asset.metadata = metadata
+		}
+	}
+	return
+}
+
+// metadataExport returns the SetMetadata call of generated file info.
+func (a *Asset) metadataExport() string {
+	if len(a.metadata) == 0 {
+		return ""
+	}
+	var keys = make([]string, 0, len(a.metadata))
+	for key := range a.metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var items = make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = fmt.Sprintf("%q: %q", key, a.metadata[key])
+	}
+	return ".SetMetadata(map[string]string{" + strings.Join(items, ", ") + "})"
+}
@@ -15,6 +15,8 @@ type Header struct {
 	storeSize  int64
 	compressed bool
 	SysPath    string
+	// Opener opens the contents, instead of SysPath file.
+	Opener func() (io.ReadCloser, error)
 }
 
 func (a *Header) Compressed(storeSize int64) *Header {
@@ -46,7 +48,13 @@ func (a *Header) LoadDigest(allW ...io.Writer) error {
 	if a.digest != nil {
 		return nil
 	}
-	f, err := os.Open(a.SysPath)
+	if a.Link() != "" || a.IsDir() {
+		// symlinks and directories does not have contents
+		d := sha256.Sum256(nil)
+		a.digest = &d
+		return nil
+	}
+	f, err := a.Open()
 	if err != nil {
 		return err
 	}
@@ -68,6 +76,14 @@ func (a *Header) LoadDigest(allW ...io.Writer) error {
 	return nil
 }
 
+// Open opens the contents, using the Opener if defined.
+func (a *Header) Open() (io.ReadCloser, error) {
+	if a.Opener != nil {
+		return a.Opener()
+	}
+	return os.Open(a.SysPath)
+}
+
 func (a *Header) Marshal(w io.Writer) (err error) {
 	if err = a.FileInfo.Marshal(w); err == nil {
 		_, err = w.Write(a.digest[:])
@@ -133,16 +133,21 @@ func (headers Headers) do(i int, w io.Writer) (err error) {
 			err = errors.Wrapf(err, "%q", a.Path())
 		}
 	}()
-	s, err := os.Stat(a.SysPath)
-	if err != nil {
-		return errors.New("os.Stat")
+	if a.Link() != "" || a.IsDir() {
+		return
 	}
+	if a.Opener == nil {
+		s, err := os.Stat(a.SysPath)
+		if err != nil {
+			return errors.New("os.Stat")
+		}
 
-	if s.Size() != a.Size() {
-		return errors.New("File size changed.")
+		if s.Size() != a.Size() {
+			return errors.New("File size changed.")
+		}
 	}
 
-	r, err := os.Open(a.SysPath)
+	r, err := a.Open()
 	if err != nil {
 		return
 	}
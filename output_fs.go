@@ -0,0 +1,264 @@
+package xbindata
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	path_helpers "github.com/moisespsena-go/path-helpers"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+// OutputFS is the file system of generated files: the Go sources, the
+// `_dev.go` local file systems, the `.gitignore` updates, the TOC, manifest
+// and checksums files and the outlined archives. See Config.OutputFS.
+//
+// The names are the OS paths. The relative paths are joined to Config.Dir,
+// if defined, or are relative to the working directory.
+type OutputFS interface {
+	// Create creates or truncates the file, creating the parent
+	// directories. The contents are committed on Close.
+	Create(name string) (io.WriteCloser, error)
+	// Append opens the file for appending, creating it if not exists.
+	Append(name string) (io.WriteCloser, error)
+	// ReadFile returns the file contents. Returns an os.IsNotExist error if
+	// file does not exists.
+	ReadFile(name string) ([]byte, error)
+	// Stat returns the file info. Returns an os.IsNotExist error if file
+	// does not exists.
+	Stat(name string) (os.FileInfo, error)
+	// Remove removes the file. Returns an os.IsNotExist error if file does
+	// not exists.
+	Remove(name string) error
+}
+
+// OSOutputFS is the OutputFS of OS file system, the default. The files
+// created are replaced atomically on Close.
+var OSOutputFS OutputFS = osOutputFS{}
+
+type osOutputFS struct{}
+
+func (osOutputFS) Create(name string) (io.WriteCloser, error) {
+	f, err := safefileCreate(name, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &osOutputFile{f}, nil
+}
+
+func (osOutputFS) Append(name string) (io.WriteCloser, error) {
+	if err := path_helpers.MkdirAllIfNotExists(filepath.Dir(name)); err != nil {
+		return nil, err
+	}
+	mode, err := path_helpers.ResolveFileMode(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, mode)
+}
+
+func (osOutputFS) ReadFile(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(f)
+	return buf.Bytes(), err
+}
+
+func (osOutputFS) Stat(name string) (os.FileInfo, error) {
+	return os.Stat(name)
+}
+
+func (osOutputFS) Remove(name string) error {
+	return os.Remove(name)
+}
+
+// osOutputFile commits the safe file on Close.
+type osOutputFile struct {
+	*safefileFile
+}
+
+func (f *osOutputFile) Close() error {
+	if err := f.safefileFile.Commit(); err != nil {
+		f.safefileFile.Close()
+		return err
+	}
+	return nil
+}
+
+// MemOutputFS is the in-memory OutputFS. It is safe for concurrent use.
+type MemOutputFS struct {
+	mu    sync.Mutex
+	files map[string]*memOutputFile
+}
+
+type memOutputFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewMemOutputFS returns a new empty in-memory OutputFS.
+func NewMemOutputFS() *MemOutputFS {
+	return &MemOutputFS{files: map[string]*memOutputFile{}}
+}
+
+func memOutputName(name string) string {
+	return filepath.ToSlash(filepath.Clean(name))
+}
+
+// WriteFile sets the file contents.
+func (fs *MemOutputFS) WriteFile(name string, data []byte) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.files[memOutputName(name)] = &memOutputFile{append([]byte(nil), data...), time.Now()}
+}
+
+func (fs *MemOutputFS) Create(name string) (io.WriteCloser, error) {
+	return &memOutputWriter{fs: fs, name: name}, nil
+}
+
+func (fs *MemOutputFS) Append(name string) (io.WriteCloser, error) {
+	w := &memOutputWriter{fs: fs, name: name}
+	if data, err := fs.ReadFile(name); err == nil {
+		w.buf.Write(data)
+	}
+	return w, nil
+}
+
+func (fs *MemOutputFS) ReadFile(name string) ([]byte, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if f, ok := fs.files[memOutputName(name)]; ok {
+		return append([]byte(nil), f.data...), nil
+	}
+	return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+}
+
+func (fs *MemOutputFS) Stat(name string) (os.FileInfo, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	name = memOutputName(name)
+	if f, ok := fs.files[name]; ok {
+		return xbcommon.NewFileInfo(name, int64(len(f.data)), 0644, f.modTime, f.modTime), nil
+	}
+	prefix := name + "/"
+	for pth, f := range fs.files {
+		if len(pth) > len(prefix) && pth[:len(prefix)] == prefix {
+			return xbcommon.NewFileInfo(name, 0, os.ModeDir|0755, f.modTime, f.modTime), nil
+		}
+	}
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+func (fs *MemOutputFS) Remove(name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	name = memOutputName(name)
+	if _, ok := fs.files[name]; !ok {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	delete(fs.files, name)
+	return nil
+}
+
+// Names returns the sorted names of files.
+func (fs *MemOutputFS) Names() (names []string) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	for name := range fs.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Files returns a copy of files contents by name.
+func (fs *MemOutputFS) Files() map[string][]byte {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	files := make(map[string][]byte, len(fs.files))
+	for name, f := range fs.files {
+		files[name] = append([]byte(nil), f.data...)
+	}
+	return files
+}
+
+type memOutputWriter struct {
+	fs   *MemOutputFS
+	name string
+	buf  bytes.Buffer
+}
+
+func (w *memOutputWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *memOutputWriter) Close() error {
+	w.fs.WriteFile(w.name, w.buf.Bytes())
+	return nil
+}
+
+// outputFS returns the config output file system.
+func (c *Config) outputFS() OutputFS {
+	if c.OutputFS == nil {
+		return OSOutputFS
+	}
+	return c.OutputFS
+}
+
+// outputPath returns the path relative to Config.Dir.
+func (c *Config) outputPath(pth string) string {
+	if c.Dir == "" || pth == "" || filepath.IsAbs(pth) {
+		return pth
+	}
+	return filepath.Join(c.Dir, pth)
+}
+
+// workDir returns the Config.Dir, or the working directory if empty.
+func (c *Config) workDir() (string, error) {
+	if c.Dir == "" {
+		return os.Getwd()
+	}
+	return c.Dir, nil
+}
+
+func (c *Config) createFile(name string) (io.WriteCloser, error) {
+	return c.outputFS().Create(c.outputPath(name))
+}
+
+func (c *Config) appendFile(name string) (io.WriteCloser, error) {
+	return c.outputFS().Append(c.outputPath(name))
+}
+
+func (c *Config) readFile(name string) ([]byte, error) {
+	return c.outputFS().ReadFile(c.outputPath(name))
+}
+
+func (c *Config) statFile(name string) (os.FileInfo, error) {
+	return c.outputFS().Stat(c.outputPath(name))
+}
+
+func (c *Config) removeFile(name string) error {
+	return c.outputFS().Remove(c.outputPath(name))
+}
+
+// writeFile writes the output file.
+func (c *Config) writeFile(name string, data []byte) error {
+	w, err := c.createFile(name)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
@@ -0,0 +1,89 @@
+package xbindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTranslateMemOutputFS(t *testing.T) {
+	wd, err := ioutil.TempDir("", "xbmemout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(wd)
+	for name, data := range map[string]string{"in/a.txt": "aaa", "in/sub/b.txt": "bbb"} {
+		pth := filepath.Join(wd, name)
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(wd); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	fs := NewMemOutputFS()
+	c := NewConfig()
+	c.Package = "gen"
+	c.Input = []InputConfig{{Path: "in", Recursive: true}}
+	c.Prefix = "in"
+	c.Output = "gen/assets.go"
+	c.Checksums = true
+	c.OutputFS = fs
+	count, err := Translate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("have %d assets, want 2", count)
+	}
+
+	want := []string{
+		"gen/.gitignore",
+		"gen/assets.go",
+		"gen/assets_SHA256SUMS",
+		"gen/assets_inputs.yml",
+		"gen/assets_toc_names.yml",
+		"gen/assets_toc_paths.yml",
+	}
+	if names := fs.Names(); strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("have files %v, want %v", names, want)
+	}
+	for name, contains := range map[string][]string{
+		"gen/.gitignore":           {"assets_toc_names.yml\n", "assets_toc_paths.yml\n"},
+		"gen/assets.go":            {"package gen\n", `"a.txt"`, `"sub/b.txt"`},
+		"gen/assets_toc_paths.yml": {`"../in/a.txt"`, `"../in/sub/b.txt"`},
+	} {
+		data, err := fs.ReadFile(name)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		for _, s := range contains {
+			if !strings.Contains(string(data), s) {
+				t.Errorf("%s: %q not found", name, s)
+			}
+		}
+	}
+
+	// the working directory has the inputs only
+	filepath.Walk(wd, func(pth string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			if rel, _ := filepath.Rel(wd, pth); !strings.HasPrefix(rel, "in"+string(filepath.Separator)) {
+				t.Errorf("file written to working directory: %s", rel)
+			}
+		}
+		return nil
+	})
+}
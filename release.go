@@ -8,10 +8,10 @@ import (
 	"bytes"
 	"compress/gzip"
 	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
 	"sort"
 	"strings"
 	"unicode/utf8"
@@ -22,33 +22,66 @@ type fsLoadCallbacksSlice []struct {
 	fun          string
 }
 
-// writeRelease writes the release code file.
-func writeRelease(w io.Writer, c *Config, toc []Asset) error {
-	err := writeReleaseHeader(w, c, toc)
-	if err != nil {
-		return err
+// writeRelease writes the release code file. If Config.ShardSize, the
+// assets data are written into the shard packages. Returns the shard files.
+func writeRelease(w io.Writer, c *Config, toc []Asset) (shards []string, err error) {
+	if c.Outlined || c.NoStore || c.ShardSize <= 0 {
+		if err = writeReleaseHeader(w, c, toc); err != nil {
+			return
+		}
+		return nil, writeReleaseAssets(w, w, c, toc, nil)
+	}
+
+	// the header imports the shard packages
+	var (
+		body bytes.Buffer
+		sw   *shardWriter
+	)
+	if sw, err = newShardWriter(c); err != nil {
+		return
+	}
+	if err = writeReleaseAssets(&body, sw, c, toc, sw); err != nil {
+		return
+	}
+	if err = writeReleaseHeader(w, c, toc, sw.imports...); err != nil {
+		return
 	}
+	_, err = w.Write(body.Bytes())
+	return sw.pths, err
+}
 
-	if !c.Outlined && !c.NoStore {
-		var start int64
-		for i := range toc {
-			err = writeReleaseAsset(start, w, c, &toc[i])
-			if err != nil {
-				return err
+// writeReleaseAssets writes the release entries of assets into w and the
+// assets data into dataW. If sw, flushes the shard after each asset.
+func writeReleaseAssets(w, dataW io.Writer, c *Config, toc []Asset, sw *shardWriter) (err error) {
+	if c.Outlined || c.NoStore {
+		return
+	}
+	var start int64
+	for i := range toc {
+		if err = c.context().Err(); err != nil {
+			return
+		}
+		if sw != nil {
+			toc[i].reader = sw.reader(&toc[i])
+		}
+		if err = writeReleaseAsset(start, w, dataW, c, &toc[i]); err != nil {
+			return
+		}
+		start += toc[i].Size
+		if sw != nil {
+			if err = sw.flush(i == len(toc)-1); err != nil {
+				return
 			}
-			start += toc[i].Size
 		}
 	}
-
-	return nil
+	return
 }
 
 // writeReleaseHeader writes output file headers.
 // This targets release builds.
-func writeReleaseHeader(w io.Writer, c *Config, toc []Asset) error {
+func writeReleaseHeader(w io.Writer, c *Config, toc []Asset, imports ...string) error {
 	var (
 		err             error
-		imports         []string
 		fsLoadCallbacks fsLoadCallbacksSlice
 	)
 
@@ -76,10 +109,18 @@ func writeReleaseHeader(w io.Writer, c *Config, toc []Asset) error {
 	if c.Outlined {
 		err = header_outlined(w, c, toc, imports...)
 	} else {
+		if len(toc) == 0 {
+			// the file infos of assets uses os
+			imports = append(imports, "-os", "-time")
+		}
 		if c.NoCompress {
 			if c.NoMemCopy {
 				err = header_uncompressed_nomemcopy(w, c, imports...)
 			} else {
+				if len(toc) == 0 || c.ShardSize > 0 {
+					// the readers are the only users of iocommon
+					imports = append(imports, "-github.com/moisespsena-go/io-common")
+				}
 				err = header_uncompressed_memcopy(w, c, imports...)
 			}
 		} else {
@@ -114,9 +155,10 @@ func writeReleaseHeader(w io.Writer, c *Config, toc []Asset) error {
 
 // writeReleaseAsset write a release entry for the given asset.
 // A release entry is a function which embeds and returns
-// the file's byte content.
-func writeReleaseAsset(start int64, w io.Writer, c *Config, asset *Asset) error {
-	fd, err := os.Open(asset.Path)
+// the file's byte content. The data and the function are written into
+// dataW.
+func writeReleaseAsset(start int64, w, dataW io.Writer, c *Config, asset *Asset) error {
+	fd, err := asset.Open()
 	if err != nil {
 		return err
 	}
@@ -124,28 +166,36 @@ func writeReleaseAsset(start int64, w io.Writer, c *Config, asset *Asset) error
 	defer fd.Close()
 
 	h := sha256.New()
-	tr := io.TeeReader(fd, h)
+	h384 := sha512.New384()
+	tr := io.TeeReader(fd, io.MultiWriter(h, h384))
 	if !c.Outlined {
 		if c.NoCompress {
 			if c.NoMemCopy {
-				err = uncompressed_nomemcopy(w, asset, tr)
+				err = uncompressed_nomemcopy(dataW, asset, tr)
 			} else {
-				err = uncompressed_memcopy(w, asset, tr)
+				err = uncompressed_memcopy(dataW, asset, tr)
 			}
 		} else {
 			if c.NoMemCopy {
-				err = compressed_nomemcopy(w, asset, tr)
+				err = compressed_nomemcopy(dataW, asset, tr)
 			} else {
-				err = compressed_memcopy(w, asset, tr)
+				err = compressed_memcopy(dataW, asset, tr)
 			}
 		}
 		if err != nil {
 			return err
 		}
 	}
-	var digest [sha256.Size]byte
+	var (
+		digest    [sha256.Size]byte
+		digest384 *[sha512.Size384]byte
+	)
 	copy(digest[:], h.Sum(nil))
-	return asset_release_common(start, w, c, asset, digest)
+	if c.IntegritySHA384 {
+		digest384 = new([sha512.Size384]byte)
+		copy(digest384[:], h384.Sum(nil))
+	}
+	return asset_release_common(start, w, c, asset, digest, digest384)
 }
 
 var (
@@ -298,10 +348,12 @@ func header_outlined(w io.Writer, c *Config, toc []Asset, imports ...string) (er
 		`br "github.com/moisespsena-go/xbindata/xbreader"`,
 		`"github.com/moisespsena-go/xbindata/outlined"`,
 		"github.com/moisespsena-go/path-helpers",
-		`fsapi "github.com/moisespsena-go/assetfs/assetfsapi"`,
 		"sync",
 		"strings",
 	)
+	if !c.OutlinedProgram {
+		imports = append(imports, "errors", "path")
+	}
 
 	if err = write_imports(w, c, imports...); err != nil {
 		return
@@ -336,6 +388,7 @@ var (
     envName      = "XB_"+strings.NewReplacer("/", "_", ".", "", "-", "").Replace(strings.ToUpper(strings.Replace(pkg, "/go-", "/", -1)))
 
 	_outlined     *outlined.Outlined
+	outlinedMu    sync.Mutex
 	outlinedPaths []string
 	outlinedPath  = os.Getenv(envName)
     ended         = os.Getenv(envName+"_ENDED") == "true"
@@ -357,11 +410,15 @@ func OutlinedPath() string {
 
 func Outlined() (archiv *outlined.Outlined, err error) {
 	if _outlined == nil {
-        mu.Lock()
-		defer mu.Unlock() 
+        outlinedMu.Lock()
+		defer outlinedMu.Unlock() 
 
-		if _outlined, err = outlined.OpenFile(outlinedPath, ended); err != nil {
-			return
+		if _outlined == nil {
+			if _outlined, err = outlined.OpenFile(outlinedPath, ended); err != nil {
+				return
+			}
+			// the compressed archive is uncompressed by OpenFile
+			outlinedPath = _outlined.Path
 		}
 	}
 	return _outlined, nil
@@ -402,10 +459,26 @@ func load() {` + preInit + `
 		return archiv.AssetsMap(OutlinedReaderFactory), nil`
 	data += `
 	}
-
-    fs = xbfs.NewFileSystem(&Assets)
-}
-
+`
+	if c.HashedNames != "" {
+		data += fmt.Sprintf(`
+	Assets.SetHashedNames(hashedNames, %v)
+`, c.HashedNames == HashedNamesOnly)
+	}
+	if c.FileSystem {
+		if c.HashedNames != "" {
+			data += `
+    fs = xbfs.NewFileSystem(Assets.Root()).SetNameResolver(&Assets)
+`
+		} else {
+			data += `
+    fs = xbfs.NewFileSystem(Assets.Root())
+`
+		}
+	}
+	data += `}
+`
+	data += `
 `
 
 	_, err = fmt.Fprintf(w, data)
@@ -442,6 +515,18 @@ func FS() fsapi.Interface {
 `
 	}
 
+	data += `
+// Integrity returns the Subresource Integrity value of asset. If the asset
+// does not exists, returns blank string.
+func Integrity(name string) string {
+	Load()
+	if asset, ok := Assets.Get(name); ok {
+		return asset.Integrity()
+	}
+	return ""
+}
+
+`
 	data += `func Load() {
     if loaded { return }
 	mu.Lock()
@@ -477,13 +562,15 @@ func compressed_nomemcopy(w io.Writer, asset *Asset, r io.Reader) error {
 		return err
 	}
 
-	gz := gzip.NewWriter(&StringWriter{Writer: w})
+	sw := &StringWriter{Writer: w}
+	gz := gzip.NewWriter(sw)
 	_, err = io.Copy(gz, r)
 	gz.Close()
 
 	if err != nil {
 		return err
 	}
+	asset.storedSize = int64(sw.c)
 
 	_, err = fmt.Fprintf(w, `"
 
@@ -501,13 +588,15 @@ func compressed_memcopy(w io.Writer, asset *Asset, r io.Reader) error {
 		return err
 	}
 
-	gz := gzip.NewWriter(&StringWriter{Writer: w})
+	sw := &StringWriter{Writer: w}
+	gz := gzip.NewWriter(sw)
 	_, err = io.Copy(gz, r)
 	gz.Close()
 
 	if err != nil {
 		return err
 	}
+	asset.storedSize = int64(sw.c)
 
 	_, err = fmt.Fprintf(w, `")
 
@@ -565,10 +654,12 @@ func %sReader()(iocommon.ReadSeekCloser, error) {
 	return err
 }
 
-func asset_release_common(start int64, w io.Writer, c *Config, asset *Asset, digest [sha256.Size]byte) error {
+func asset_release_common(start int64, w io.Writer, c *Config, asset *Asset, digest [sha256.Size]byte, digest384 *[sha512.Size384]byte) error {
 	var readerFunc string
 	if c.Outlined {
 		readerFunc = fmt.Sprintf("newOpener(%d, %d)", start, asset.Size)
+	} else if asset.reader != "" {
+		readerFunc = asset.reader
 	} else {
 		readerFunc = fmt.Sprintf("%sReader", asset.Func)
 	}
@@ -576,7 +667,11 @@ func asset_release_common(start int64, w io.Writer, c *Config, asset *Asset, dig
 	if err != nil {
 		return err
 	}
-	_, err = fmt.Fprintf(w, `var %s = bc.NewFile(bc.NewFileInfo(%q, %s), %s,  &%#v)
-`, asset.Func, asset.Name, info, readerFunc, digest)
+	var set384 string
+	if digest384 != nil {
+		set384 = fmt.Sprintf(".SetDigest384(&%#v)", *digest384)
+	}
+	_, err = fmt.Fprintf(w, `var %s = bc.NewFile(bc.NewFileInfo(%q, %s)%s, %s,  &%#v)%s
+`, asset.Func, asset.Name, info, asset.infoSetters(), readerFunc, digest, set384)
 	return err
 }
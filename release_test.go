@@ -29,7 +29,7 @@ func TestSanitize(t *testing.T) {
 func TestEmptyFile(t *testing.T) {
 	buf := new(bytes.Buffer)
 	c := &Config{NoCompress: true, NoMemCopy: false}
-	err := writeReleaseAsset(buf, c, &Asset{Func: "hello", Path: "testdata/empty/empty_file"})
+	err := writeReleaseAsset(0, buf, buf, c, &Asset{Func: "hello", Path: "testdata/empty/empty_file"})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,123 @@
+package xbindata
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	CodecNone = "none"
+	CodecGzip = "gzip"
+)
+
+// AssetResult holds the build information of a single asset.
+type AssetResult struct {
+	Name       string    `json:"name"`
+	HashedName string    `json:"hashed_name,omitempty"`
+	Path       string    `json:"path"`
+	Size       int64     `json:"size"`
+	StoredSize int64     `json:"stored_size"`
+	Codec      string    `json:"codec"`
+	Digest     string    `json:"digest"`
+	Mode       string    `json:"mode"`
+	ModTime    time.Time `json:"mod_time"`
+	// Metadata is the asset metadata.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// BuildResult holds the structured result of a Translate call.
+type BuildResult struct {
+	Package   string         `json:"package"`
+	Outlined  bool           `json:"outlined"`
+	Assets    []*AssetResult `json:"assets"`
+	Count     int            `json:"count"`
+	TotalSize int64          `json:"total_size"`
+	// TotalStoredSize is the sum of assets stored size or, of compressed
+	// outlined archive, the archive size.
+	TotalStoredSize int64 `json:"total_stored_size"`
+	// Outputs are the generated files.
+	Outputs   []string      `json:"outputs"`
+	StartedAt time.Time     `json:"started_at"`
+	Duration  time.Duration `json:"duration"`
+}
+
+func (r *BuildResult) addOutput(pth ...string) {
+	r.Outputs = append(r.Outputs, pth...)
+}
+
+// outlinedCompressed returns if the outlined archive file is compressed.
+func (c *Config) outlinedCompressed() bool {
+	return c.Outlined && !c.OutlinedProgram && !c.NoCompress
+}
+
+func (r *BuildResult) setAssets(c *Config, toc []Asset) (err error) {
+	r.Assets = make([]*AssetResult, len(toc))
+	r.Count = len(toc)
+	for i := range toc {
+		var (
+			asset = &toc[i]
+			info  os.FileInfo
+			res   = &AssetResult{Name: asset.OriginalName(), HashedName: asset.hashedName, Path: asset.Path, Size: asset.Size, Metadata: asset.metadata}
+		)
+		if info, err = asset.Info(); err != nil {
+			return
+		}
+		res.Mode = info.Mode().String()
+		res.ModTime = info.ModTime()
+
+		if d, err := asset.Digest(); err != nil {
+			return err
+		} else {
+			res.Digest = hex.EncodeToString(d[:])
+		}
+
+		res.Codec, res.StoredSize = CodecNone, asset.Size
+		switch {
+		case asset.storedSize > 0:
+			res.Codec, res.StoredSize = CodecGzip, asset.storedSize
+		case c.outlinedCompressed():
+			// the archive is compressed as a whole, see
+			// BuildResult.TotalStoredSize
+			res.Codec = CodecGzip
+		}
+
+		r.TotalSize += res.Size
+		r.TotalStoredSize += res.StoredSize
+		r.Assets[i] = res
+	}
+	return
+}
+
+// BuildReport holds the results of many Translate calls.
+type BuildReport struct {
+	Packages        []*BuildResult `json:"packages"`
+	Count           int            `json:"count"`
+	TotalSize       int64          `json:"total_size"`
+	TotalStoredSize int64          `json:"total_stored_size"`
+	StartedAt       time.Time      `json:"started_at"`
+	Duration        time.Duration  `json:"duration"`
+}
+
+func NewBuildReport() *BuildReport {
+	return &BuildReport{StartedAt: time.Now()}
+}
+
+func (r *BuildReport) Add(result ...*BuildResult) {
+	for _, result := range result {
+		r.Packages = append(r.Packages, result)
+		r.Count += result.Count
+		r.TotalSize += result.TotalSize
+		r.TotalStoredSize += result.TotalStoredSize
+	}
+	r.Duration = time.Since(r.StartedAt)
+}
+
+// WriteJSON writes indented JSON report into w.
+func (r *BuildReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,92 @@
+package xbindata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildResult(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbresult")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = os.Mkdir(filepath.Join(dir, "static"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "static", "a.txt"), bytes.Repeat([]byte("a"), 1000), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report := NewBuildReport()
+	for _, tt := range []struct {
+		name  string
+		opts  []Option
+		codec string
+	}{
+		{"embedded", nil, CodecGzip},
+		{"embedded-nocompress", []Option{WithNoCompress()}, CodecNone},
+		{"outlined", []Option{WithOutlined("api/api.go"), WithOutput("assets.xb")}, CodecGzip},
+		{"outlined-nocompress", []Option{WithOutlined("api/api.go"), WithOutput("assets.xb"), WithNoCompress()}, CodecNone},
+	} {
+		fs := NewMemOutputFS()
+		b, err := NewBuilder(append([]Option{
+			WithDir(dir),
+			WithPackage("assets"),
+			WithInput(InputConfig{Path: "static"}),
+			WithPrefix("static"),
+			WithOutputFS(fs),
+		}, tt.opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := b.Build(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		report.Add(result)
+
+		a := result.Assets[0]
+		if a.Name != "a.txt" || a.Size != 1000 || a.Codec != tt.codec || len(a.Digest) != 64 {
+			t.Errorf("%s: bad asset result %+v", tt.name, a)
+		}
+		if compressed := result.TotalStoredSize < result.TotalSize; compressed != (tt.codec == CodecGzip) {
+			t.Errorf("%s: bad stored size %d of %d", tt.name, result.TotalStoredSize, result.TotalSize)
+		}
+		if strings.HasPrefix(tt.name, "outlined") {
+			info, err := fs.Stat(result.Outputs[len(result.Outputs)-1])
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+			} else if tt.codec == CodecGzip && info.Size() != result.TotalStoredSize {
+				t.Errorf("%s: have stored size %d, want archive size %d", tt.name, result.TotalStoredSize, info.Size())
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Packages []struct {
+			Package  string
+			Outlined bool
+			Assets   []struct{ Name, Codec string }
+		}
+		Count     int
+		TotalSize int64 `json:"total_size"`
+	}
+	if err = json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Packages) != 4 || decoded.Count != 4 || decoded.TotalSize != 4000 ||
+		!decoded.Packages[2].Outlined || decoded.Packages[2].Assets[0].Codec != CodecGzip {
+		t.Errorf("bad report %s", buf.Bytes())
+	}
+}
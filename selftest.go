@@ -0,0 +1,227 @@
+package xbindata
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// writeSelfTest writes the `<api base>_xb_test.go` file, testing the
+// generated package: opens each asset of toc by the package API and checks
+// the size, digest and symlink target, and checks the assets tree. The
+// `<api base>_xb_fs_test.go` file runs the testing/fstest.TestFS. The
+// archive is the absolute path of outlined archive, used by the test if
+// the package does not find it. Returns the written files.
+func writeSelfTest(c *Config, toc, dirs []Asset, archive string) (pths []string, err error) {
+	var (
+		api     = c.apiOutput()
+		base    = filepath.Join(filepath.Dir(api), strings.TrimSuffix(filepath.Base(api), ".go")+"_xb")
+		buf     bytes.Buffer
+		treeDir = map[string]bool{}
+	)
+
+	addDirs := func(name string) {
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			treeDir[dir] = true
+		}
+	}
+
+	writeGeneratedHeader(&buf, c)
+	buf.WriteString(`import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+`)
+	if archive != "" {
+		buf.WriteString(`	"os"
+	"path/filepath"
+`)
+	}
+	buf.WriteString(`	"sort"
+	"strings"
+	"testing"
+
+	bc "github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+// xbTestAssets are the assets at generation time.
+var xbTestAssets = []struct {
+	name, link, digest string
+	size               int64
+}{
+`)
+
+	for i := range toc {
+		asset := &toc[i]
+		addDirs(asset.Name)
+		if asset.link != "" {
+			fmt.Fprintf(&buf, "\t{name: %q, link: %q},\n", asset.Name, asset.link)
+			continue
+		}
+		d, err := asset.Digest()
+		if err != nil {
+			return nil, err
+		}
+		digest := hex.EncodeToString(d[:])
+		fmt.Fprintf(&buf, "\t{name: %q, digest: %q, size: %d},\n", asset.Name, digest, asset.Size)
+		if c.HashedNames == HashedNamesAdd && asset.hashedName != "" {
+			addDirs(asset.hashedName)
+			fmt.Fprintf(&buf, "\t{name: %q, digest: %q, size: %d},\n", asset.hashedName, digest, asset.Size)
+		}
+	}
+
+	for i := range dirs {
+		treeDir[dirs[i].Name] = true
+		addDirs(dirs[i].Name)
+	}
+
+	var treeDirs []string
+	for dir := range treeDir {
+		treeDirs = append(treeDirs, dir)
+	}
+	sort.Strings(treeDirs)
+
+	buf.WriteString(`}
+
+// xbTestDirs are the directories of assets tree.
+var xbTestDirs = []string{
+`)
+	for _, dir := range treeDirs {
+		fmt.Fprintf(&buf, "\t%q,\n", dir)
+	}
+	buf.WriteString("}\n")
+
+	if archive != "" {
+		var wd, dir string
+		if wd, err = c.workDir(); err != nil {
+			return
+		}
+		if dir = c.outputPath(filepath.Dir(api)); !filepath.IsAbs(dir) {
+			dir = filepath.Join(wd, dir)
+		}
+		if archive, err = filepath.Rel(dir, archive); err != nil {
+			return
+		}
+		// the compressed archive is uncompressed on first open
+		archives := fmt.Sprintf("%q", filepath.ToSlash(archive))
+		if strings.HasSuffix(archive, ".gz") {
+			archives += fmt.Sprintf(", %q", filepath.ToSlash(strings.TrimSuffix(archive, ".gz")))
+		}
+		fmt.Fprintf(&buf, `
+// xbTestArchive sets the outlined archive path, relative to the package
+// directory, if not defined by environment.
+var xbTestArchive = func() string {
+	if outlinedPath == "" {
+		for _, pth := range []string{%s} {
+			if _, err := os.Stat(filepath.FromSlash(pth)); err == nil {
+				outlinedPath = filepath.FromSlash(pth)
+				break
+			}
+		}
+	}
+	return outlinedPath
+}()
+`, archives)
+	}
+
+	buf.WriteString(`
+func TestXbAssets(t *testing.T) {
+	Load()
+	for _, a := range xbTestAssets {
+		asset, ok := Assets.Get(a.name)
+		if !ok {
+			t.Errorf("%s: not found", a.name)
+			continue
+		}
+		if a.link != "" {
+			if l, ok := asset.(bc.Linker); !ok || l.Link() != a.link {
+				t.Errorf("%s: bad symlink target", a.name)
+			}
+			continue
+		}
+		r, err := asset.Reader()
+		if err != nil {
+			t.Errorf("%s: open failed: %v", a.name, err)
+			continue
+		}
+		data, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Errorf("%s: read failed: %v", a.name, err)
+			continue
+		}
+		if int64(len(data)) != a.size {
+			t.Errorf("%s: have size %d, want %d", a.name, len(data), a.size)
+		}
+		if d := sha256.Sum256(data); hex.EncodeToString(d[:]) != a.digest {
+			t.Errorf("%s: have digest %x, want %s", a.name, d, a.digest)
+		}
+	}
+}
+
+func TestXbTree(t *testing.T) {
+	Load()
+	var want, have []string
+	for _, a := range xbTestAssets {
+		want = append(want, a.name)
+	}
+	want = append(want, xbTestDirs...)
+	err := Assets.Root().Walk(func(dir, name string, n bc.Node, data interface{}) (interface{}, error) {
+		have = append(have, n.Path())
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(want)
+	sort.Strings(have)
+	if strings.Join(have, "\n") != strings.Join(want, "\n") {
+		t.Errorf("bad tree:\nhave %q\nwant %q", have, want)
+	}
+}
+`)
+
+	var fmted []byte
+	if fmted, err = format.Source(buf.Bytes()); err != nil {
+		return
+	}
+	pth := base + "_test.go"
+	if err = c.writeFile(pth, fmted); err != nil {
+		return
+	}
+	pths = append(pths, pth)
+
+	// the io/fs test requires go1.16
+	buf.Reset()
+	writeGeneratedHeader(&buf, c, "go1.16")
+	buf.WriteString(`import (
+	"testing"
+	"testing/fstest"
+
+	bc "github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+func TestXbFS(t *testing.T) {
+	Load()
+	var names []string
+	for _, a := range xbTestAssets {
+		names = append(names, a.name)
+	}
+	if err := fstest.TestFS(bc.NewIOFS(Assets.Root()), names...); err != nil {
+		t.Fatal(err)
+	}
+}
+`)
+
+	pth = base + "_fs_test.go"
+	if err = c.writeFile(pth, buf.Bytes()); err != nil {
+		return
+	}
+	pths = append(pths, pth)
+	return
+}
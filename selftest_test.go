@@ -0,0 +1,61 @@
+package xbindata
+
+import (
+	"bytes"
+	"context"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSelfTestFormatted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbselftest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	pth := filepath.Join(dir, "static", "a.txt")
+	if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(pth, []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, outlined := range []bool{false, true} {
+		opts := []Option{
+			WithDir(dir),
+			WithPackage("assets"),
+			WithInput(InputConfig{Path: "static", Recursive: true}),
+			WithSelfTest(),
+		}
+		if outlined {
+			opts = append(opts, WithOutput("assets.xb"), WithOutlined("assets.go"))
+		} else {
+			opts = append(opts, WithOutput("assets.go"))
+		}
+		fs := NewMemOutputFS()
+		opts = append(opts, WithOutputFS(fs))
+		b, err := NewBuilder(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = b.Build(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		data, err := fs.ReadFile(filepath.Join(dir, "assets_xb_test.go"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the imports are sorted too
+		fmted, err := format.Source(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(data, fmted) {
+			t.Errorf("outlined=%v: the self test isn't formatted", outlined)
+		}
+	}
+}
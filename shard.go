@@ -0,0 +1,147 @@
+package xbindata
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// shardFile is the file name of shard packages.
+const shardFile = "data.go"
+
+// writeGeneratedHeader writes the generated header, the build tags of
+// config and tags and the package declaration.
+func writeGeneratedHeader(buf *bytes.Buffer, c *Config, tags ...string) {
+	writeGeneratedHeaderPackage(buf, c, path.Base(c.Package), tags...)
+}
+
+func writeGeneratedHeaderPackage(buf *bytes.Buffer, c *Config, pkg string, tags ...string) {
+	buf.WriteString("// " + generatedHeader + "\n")
+	for _, tag := range append(tags, c.Tags...) {
+		buf.WriteString("// +build " + tag + "\n")
+	}
+	if len(tags)+len(c.Tags) > 0 {
+		buf.WriteByte('\n')
+	}
+	fmt.Fprintf(buf, "package %s\n\n", pkg)
+}
+
+// shardDir returns the directory of nth (from 1) shard package of output.
+func shardDir(output string, n int) string {
+	return fmt.Sprintf("%s_shard_%03d", strings.TrimSuffix(output, ".go"), n)
+}
+
+// shardWriter writes the assets data into the shard packages of about
+// Config.ShardSize bytes. Each shard package is compiled apart, so the
+// compiler holds one shard at a time. The asset data is not split across
+// shards.
+type shardWriter struct {
+	c *Config
+	// pkg is the import path of output directory.
+	pkg   string
+	buf   bytes.Buffer
+	funcs []string
+	// pths are the written shard files.
+	pths []string
+	// imports are the shard packages imports of output file.
+	imports []string
+}
+
+func newShardWriter(c *Config) (s *shardWriter, err error) {
+	s = &shardWriter{c: c}
+	if strings.Contains(c.Package, "/") {
+		s.pkg = c.Package
+	} else if s.pkg, err = goImportPath(filepath.Dir(c.outputPath(c.Output))); err != nil {
+		return nil, fmt.Errorf("shard: %v (set the package import path)", err)
+	}
+	return
+}
+
+// reader returns the reader expression of asset data of current shard.
+func (s *shardWriter) reader(asset *Asset) string {
+	s.funcs = append(s.funcs, asset.Func)
+	return fmt.Sprintf("xbshard%03d.Readers[%q]", len(s.pths)+1, asset.Func)
+}
+
+func (s *shardWriter) Write(p []byte) (n int, err error) {
+	if s.buf.Len() == 0 {
+		writeGeneratedHeaderPackage(&s.buf, s.c, fmt.Sprintf("shard%03d", len(s.pths)+1))
+		if err = writeShardHeader(&s.buf, s.c); err != nil {
+			return
+		}
+	}
+	return s.buf.Write(p)
+}
+
+// flush writes the shard file if it is full or, if force, not empty.
+func (s *shardWriter) flush(force bool) (err error) {
+	if s.buf.Len() == 0 || (!force && int64(s.buf.Len()) < s.c.ShardSize) {
+		return
+	}
+	s.buf.WriteString("// Readers are the readers of assets data, by asset func name.\n" +
+		"var Readers = map[string]func() (iocommon.ReadSeekCloser, error){\n")
+	for _, f := range s.funcs {
+		fmt.Fprintf(&s.buf, "\t%q: %sReader,\n", f, f)
+	}
+	s.buf.WriteString("}\n")
+
+	var (
+		n   = len(s.pths) + 1
+		dir = shardDir(s.c.Output, n)
+		pth = filepath.Join(dir, shardFile)
+	)
+	if err = s.c.writeFile(pth, s.buf.Bytes()); err != nil {
+		return
+	}
+	s.pths = append(s.pths, pth)
+	s.imports = append(s.imports, fmt.Sprintf(`xbshard%03d "%s/%s"`, n, s.pkg, filepath.Base(dir)))
+	s.buf.Reset()
+	s.funcs = nil
+	return
+}
+
+// writeShardHeader writes the imports and the data reader of shard
+// package.
+func writeShardHeader(w io.Writer, c *Config) error {
+	// the shard has the data readers only
+	imports := []string{
+		"-os",
+		"-time",
+		"-sync",
+		`-bc "github.com/moisespsena-go/xbindata/xbcommon"`,
+		`-"github.com/moisespsena-go/xbindata/xbfs"`,
+		`-fsapi "github.com/moisespsena-go/assetfs/assetfsapi"`,
+	}
+	if c.NoCompress {
+		if c.NoMemCopy {
+			return header_uncompressed_nomemcopy(w, c, imports...)
+		}
+		return header_uncompressed_memcopy(w, c, imports...)
+	}
+	if c.NoMemCopy {
+		return header_compressed_nomemcopy(w, c, imports...)
+	}
+	return header_compressed_memcopy(w, c, imports...)
+}
+
+// removeShards removes the shard packages after the nth, written by the
+// previous generations.
+func removeShards(c *Config, n int) (err error) {
+	for ; ; n++ {
+		dir := shardDir(c.Output, n+1)
+		if err = c.removeFile(filepath.Join(dir, shardFile)); err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return
+		}
+		// the memory output file system has no directories
+		if err = c.removeFile(dir); err != nil && !os.IsNotExist(err) {
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+//go:build linux
+// +build linux
+
+package xbindata_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/dustin/go-humanize"
+
+	bindata "github.com/moisespsena-go/xbindata"
+)
+
+// BenchmarkShardBuild reports the peak memory of `go build` of the package
+// generated from a large synthetic asset set, for the single file and the
+// sharded outputs. XB_BENCH_SIZE sets the size of asset set (default 32MB).
+// The peak memory is the max RSS of the go command and the compiler.
+//
+//	$ go test -run - -bench ShardBuild -benchtime 1x
+func BenchmarkShardBuild(b *testing.B) {
+	size := uint64(32 << 20)
+	if s := os.Getenv("XB_BENCH_SIZE"); s != "" {
+		var err error
+		if size, err = humanize.ParseBytes(s); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	for _, shardSize := range []int64{0, 4 << 20} {
+		name := "single"
+		if shardSize > 0 {
+			name = fmt.Sprintf("shard-%dMB", shardSize>>20)
+		}
+		b.Run(name, func(b *testing.B) {
+			var peak int64
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				// into current module, for the build of generated package
+				dir, err := ioutil.TempDir(".", "_shardbench")
+				if err != nil {
+					b.Fatal(err)
+				}
+				defer os.RemoveAll(dir)
+
+				if err = writeSyntheticAssets(filepath.Join(dir, "assets"), size); err != nil {
+					b.Fatal(err)
+				}
+				// the exec'ed process inherits the peak memory of benchmark
+				// process, so the package is generated apart
+				cmd := exec.Command(os.Args[0], "-test.run", "^TestShardBenchGenerate$")
+				cmd.Env = append(os.Environ(),
+					"XB_SHARD_BENCH_DIR="+dir,
+					"XB_SHARD_BENCH_SIZE="+strconv.FormatInt(shardSize, 10))
+				if out, err := cmd.CombinedOutput(); err != nil {
+					b.Fatalf("%v: %s", err, out)
+				}
+
+				cmd = exec.Command("go", "build", "./"+filepath.Join(dir, "gen"))
+				cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+				b.StartTimer()
+				if err = cmd.Run(); err != nil {
+					b.Fatal(err)
+				}
+				b.StopTimer()
+				// the max RSS of the go command children, in KB
+				if rss := cmd.ProcessState.SysUsage().(*syscall.Rusage).Maxrss; rss > peak {
+					peak = rss
+				}
+				os.RemoveAll(dir)
+			}
+			b.ReportMetric(float64(peak)/1024, "peak-MB")
+		})
+	}
+}
+
+// TestShardBenchGenerate generates the package of BenchmarkShardBuild.
+func TestShardBenchGenerate(t *testing.T) {
+	dir := os.Getenv("XB_SHARD_BENCH_DIR")
+	if dir == "" {
+		t.Skip("run by BenchmarkShardBuild")
+	}
+	shardSize, err := strconv.ParseInt(os.Getenv("XB_SHARD_BENCH_SIZE"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = bindata.Translate(&bindata.Config{
+		Package:   "gen",
+		Input:     []bindata.InputConfig{{Path: filepath.Join(dir, "assets"), Recursive: true}},
+		Output:    filepath.Join(dir, "gen", "assets.go"),
+		ShardSize: shardSize,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeSyntheticAssets writes random files of 256KB, totaling size bytes,
+// into dir. The random data defeats the compression and the build cache.
+func writeSyntheticAssets(dir string, size uint64) (err error) {
+	const fileSize = 256 << 10
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	data := make([]byte, fileSize)
+	for i := 0; uint64(i*fileSize) < size; i++ {
+		rand.Read(data)
+		if err = ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("file%04d.bin", i)), data, 0644); err != nil {
+			return
+		}
+	}
+	return
+}
@@ -32,6 +32,7 @@ func (i *ManyConfigInput) format(ctx context.Context, key, value string, envs ..
 			"fpjoin":  filepath.Join,
 			"Env":     env,
 			"PKG":     i.Pkg,
+			"Profile": ContextProfile(ctx),
 		}); err != nil {
 			return
 		}
@@ -121,7 +121,7 @@ func writeTOCTree(w io.Writer, toc []Asset) error {
 }
 
 // writeTOC writes the table of contents file.
-func writeTOC(c *Config, buf *bytes.Buffer, toc []Asset) error {
+func writeTOC(c *Config, buf *bytes.Buffer, toc, dirs []Asset) error {
 	writeTOCHeader(buf)
 
 	var start int64
@@ -136,6 +136,12 @@ func writeTOC(c *Config, buf *bytes.Buffer, toc []Asset) error {
 		start += toc[i].Size
 	}
 
+	for i := range dirs {
+		if err := writeTOCDir(c, buf, &dirs[i]); err != nil {
+			return err
+		}
+	}
+
 	writeTOCFooter(c, buf)
 	return nil
 }
@@ -157,17 +163,40 @@ func writeTOCAsset(c *Config, start int64, w io.Writer, asset *Asset) (err error
 	return err
 }
 
+// writeTOCDir writes a TOC directory entry.
+func writeTOCDir(c *Config, w io.Writer, dir *Asset) (err error) {
+	info, err := dir.InfoExport(c)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "\n\t\tbc.NewFile(bc.NewFileInfo(%q, %s), nil, nil),", dir.Name, info)
+	return err
+}
+
 // writeTOCFooter writes the table of contents file footer.
 func writeTOCFooter(c *Config, buf *bytes.Buffer) {
 	data := `
 	)
 `
+	if c.HashedNames != "" {
+		data += fmt.Sprintf(`
+	Assets.SetHashedNames(hashedNames, %v)
+`, c.HashedNames == HashedNamesOnly)
+	}
 	if c.FileSystem {
-		data += `
-	DefaultFS = xbfs.NewFileSystem(Assets)
+		if c.HashedNames != "" {
+			data += `
+	fs = xbfs.NewFileSystem(Assets.Root()).SetNameResolver(Assets)
 `
+		} else {
+			data += `
+	fs = xbfs.NewFileSystem(Assets.Root())
+`
+		}
 	}
 	data += `}
+
+func load() { LoadDefault() }
 `
 	buf.WriteString(data)
 }
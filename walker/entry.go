@@ -0,0 +1,77 @@
+package walker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Entry is a asset entry of custom walker. The `.xbwalk` programs write one
+// JSON encoded Entry per line to stdout, using EntryEncoder.
+type Entry struct {
+	// Path is the file path, absolute or relative to input path.
+	Path string `json:"path"`
+	// Name overrides the asset name, derived from Path.
+	Name string `json:"name,omitempty"`
+	// NameSpace is the asset name prefix.
+	NameSpace string `json:"ns,omitempty"`
+	// Metadata are the asset metadata values.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// EntryEncoder writes the entries as JSON lines.
+type EntryEncoder struct {
+	enc *json.Encoder
+}
+
+// NewEntryEncoder returns a new EntryEncoder that writes to w.
+func NewEntryEncoder(w io.Writer) *EntryEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &EntryEncoder{enc}
+}
+
+// Encode writes the entry line.
+func (e *EntryEncoder) Encode(entry Entry) error {
+	return e.enc.Encode(entry)
+}
+
+// ReadEntries reads the entry lines from r and calls cb for each entry. For
+// compatibility, the lines that aren't JSON objects are read as paths.
+func ReadEntries(r io.Reader, cb func(entry Entry) error) (err error) {
+	var (
+		br   = bufio.NewReader(r)
+		line []byte
+		num  int
+	)
+	for {
+		if line, err = br.ReadBytes('\n'); err != nil && err != io.EOF {
+			return
+		}
+		eof := err == io.EOF
+		num++
+
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var entry Entry
+			if line[0] == '{' {
+				if err = json.Unmarshal(line, &entry); err != nil {
+					return fmt.Errorf("line %d: %v", num, err)
+				}
+				if entry.Path == "" {
+					return fmt.Errorf("line %d: path not set", num)
+				}
+			} else {
+				entry.Path = string(line)
+			}
+			if err = cb(entry); err != nil {
+				return
+			}
+		}
+
+		if eof {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package walker
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestReadEntries(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEntryEncoder(&buf)
+	want := []Entry{
+		{Path: "a/new\nline.txt", Name: "b.txt", NameSpace: "ns", Metadata: map[string]string{"k": "<v>"}},
+		{Path: "plain.txt"},
+		{Path: "last.txt"},
+	}
+	if err := enc.Encode(want[0]); err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("\n  plain.txt \nlast.txt")
+
+	var got []Entry
+	if err := ReadEntries(&buf, func(entry Entry) error {
+		got = append(got, entry)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if err := ReadEntries(bytes.NewBufferString(`{"name":"x"}`), func(Entry) error { return nil }); err == nil {
+		t.Error("entry without path accepted")
+	}
+}
@@ -13,6 +13,31 @@ import (
 
 const XbWalkName = ".xbwalk"
 
+// Symlinks policies.
+const (
+	// SymlinksFollow walks the symlink target (the default). Each symlink
+	// to a directory is walked, except the symlinks to the directory being
+	// walked or to its parents, which are cycles.
+	SymlinksFollow = "follow"
+	// SymlinksPreserve passes the symlink itself to the callback, with the
+	// FileInfo.Link target.
+	SymlinksPreserve = "preserve"
+	// SymlinksSkip ignores the symlinks.
+	SymlinksSkip = "skip"
+	// SymlinksError fails the walk on symlinks.
+	SymlinksError = "error"
+)
+
+// ValidSymlinks returns if policy is a valid symlinks policy. The empty
+// policy is the same as SymlinksFollow.
+func ValidSymlinks(policy string) bool {
+	switch policy {
+	case "", SymlinksFollow, SymlinksPreserve, SymlinksSkip, SymlinksError:
+		return true
+	}
+	return false
+}
+
 type (
 	WalkCallback = func(info FileInfo) error
 	WalkFunc     = func(visited *map[string]bool, recursive bool, cb WalkCallback) (err error)
@@ -22,6 +47,14 @@ type FileInfo struct {
 	os.FileInfo
 	Path       string
 	NamePrefix []string
+	// Link is the symlink target, in SymlinksPreserve policy.
+	Link string
+	// RealPath is the file system path, if Path is a virtual path.
+	RealPath string
+	// AssetName overrides the asset name, derived from Path. See Entry.Name.
+	AssetName string
+	// Metadata are the asset metadata values. See Entry.Metadata.
+	Metadata map[string]string
 }
 
 func (info FileInfo) SetNamePrefix(prefix ...string) FileInfo {
@@ -37,7 +70,13 @@ type Walker struct {
 	IgnoreRes    []*regexp.Regexp
 	IgnoreGlobs  []glob.Glob
 	IgnoreFuncs  []func(pth string) bool
-	depth        int
+	// Symlinks is the symlinks policy. Defaults to SymlinksFollow.
+	Symlinks string
+	// Dirs passes the walked sub directories to the callback.
+	Dirs  bool
+	depth int
+	// parents are the real paths of directories being walked.
+	parents []string
 }
 
 func New() *Walker {
@@ -179,9 +218,20 @@ func (w Walker) walk(fi os.FileInfo, pth string, cb WalkCallback) (err error) {
 		return cb(FileInfo{FileInfo: fi, Path: pth})
 	}
 
+	if w.Dirs && w.depth > 0 {
+		if err = cb(FileInfo{FileInfo: fi, Path: pth}); err != nil {
+			return
+		}
+	}
+
+	w.depth++
+
 	var list []os.FileInfo
 
 	(*w.VisitedPaths)[pth] = true
+	if real, err := realPath(pth); err == nil {
+		w.parents = append(w.parents[:len(w.parents):len(w.parents)], real)
+	}
 	fd, err := os.Open(pth)
 	if err != nil {
 		return err
@@ -211,6 +261,27 @@ func (w Walker) walk(fi os.FileInfo, pth string, cb WalkCallback) (err error) {
 			}
 			continue
 		} else if fi.Mode()&os.ModeSymlink == os.ModeSymlink {
+			switch w.Symlinks {
+			case SymlinksSkip:
+				continue
+			case SymlinksError:
+				return errors.Errorf("%q is a symlink", pth)
+			case SymlinksPreserve:
+				if !w.Accepts(pth) {
+					continue
+				}
+				var link string
+				if link, err = os.Readlink(pth); err != nil {
+					return err
+				}
+				if err = cb(FileInfo{FileInfo: fi, Path: pth, Link: link}); err != nil {
+					return
+				}
+				continue
+			}
+			if w.isParent(pth) {
+				continue
+			}
 			var linkPath string
 			if linkPath, err = os.Readlink(pth); err != nil {
 				return err
@@ -239,3 +310,26 @@ func (w Walker) walk(fi os.FileInfo, pth string, cb WalkCallback) (err error) {
 
 	return nil
 }
+
+// isParent returns if the symlink target is the directory being walked or
+// one of its parents.
+func (w Walker) isParent(link string) bool {
+	real, err := realPath(link)
+	if err != nil {
+		return false
+	}
+	for _, p := range w.parents {
+		if p == real {
+			return true
+		}
+	}
+	return false
+}
+
+// realPath returns the absolute path of pth, without symlinks.
+func realPath(pth string) (real string, err error) {
+	if real, err = filepath.EvalSymlinks(pth); err != nil {
+		return
+	}
+	return filepath.Abs(real)
+}
@@ -0,0 +1,43 @@
+package walker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestWalkSymlinks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbwalker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err = os.Mkdir(filepath.Join(dir, "v2"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "v2", "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for link, target := range map[string]string{"aa": "v2", "latest": "v2", "zz": "v2", "v2/up": "..", "v2/self": "."} {
+		if err = os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var paths []string
+	if err = (Walker{Recursive: true}).Walk(dir, func(info FileInfo) error {
+		rel, _ := filepath.Rel(dir, info.Path)
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(paths)
+	// each alias is walked, the cycles are not
+	if want := []string{"aa/a.txt", "latest/a.txt", "v2/a.txt", "zz/a.txt"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("have paths %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,76 @@
+package xbindata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/moisespsena-go/xbindata/walker"
+)
+
+// InputWalker is a in-process input walker. It calls cb for each entry of
+// input. See RegisterWalker.
+type InputWalker func(input *InputConfig, prod bool, cb func(entry walker.Entry) error) error
+
+var inputWalkers = struct {
+	sync.RWMutex
+	m map[string]InputWalker
+}{m: map[string]InputWalker{}}
+
+// RegisterWalker registers the in-process input walker by name, used by the
+// inputs with the `walker: NAME` option when xbindata is used as library.
+// It is a alternative to `.xbwalk` programs, without the compile step.
+// Panics if the name is already registered.
+func RegisterWalker(name string, w InputWalker) {
+	if w == nil {
+		panic("xbindata: RegisterWalker walker is nil")
+	}
+	inputWalkers.Lock()
+	defer inputWalkers.Unlock()
+	if _, dup := inputWalkers.m[name]; dup {
+		panic("xbindata: RegisterWalker called twice for walker " + name)
+	}
+	inputWalkers.m[name] = w
+}
+
+// GetWalker returns the registered input walker.
+func GetWalker(name string) (w InputWalker, ok bool) {
+	inputWalkers.RLock()
+	defer inputWalkers.RUnlock()
+	w, ok = inputWalkers.m[name]
+	return
+}
+
+// Walkers returns the names of registered input walkers.
+func Walkers() (names []string) {
+	inputWalkers.RLock()
+	defer inputWalkers.RUnlock()
+	for name := range inputWalkers.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// walkEntry calls cb with the file info of walker entry. The relative entry
+// paths are joined to root.
+func walkEntry(root string, entry walker.Entry, cb walker.WalkCallback) (err error) {
+	pth := filepath.FromSlash(entry.Path)
+	if !filepath.IsAbs(pth) {
+		pth = filepath.Join(root, pth)
+	}
+	var info os.FileInfo
+	if info, err = os.Stat(pth); err != nil {
+		return
+	}
+	if info.IsDir() {
+		return fmt.Errorf("walker entry %q is a directory", entry.Path)
+	}
+	fi := walker.FileInfo{FileInfo: info, Path: pth, AssetName: entry.Name, Metadata: entry.Metadata}
+	if entry.NameSpace != "" {
+		fi = fi.SetNamePrefix(entry.NameSpace)
+	}
+	return cb(fi)
+}
@@ -15,10 +15,12 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"reflect"
 	"strconv"
@@ -27,12 +29,42 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
 
 	"github.com/moisespsena-go/xbindata"
 )
 
+// unmarshalConfig decodes the config file into dest. The YAML and JSON
+// files are loaded by readConfigFile before.
 func unmarshalConfig(dest interface{}) error {
-	return viper.Unmarshal(dest, func(config *mapstructure.DecoderConfig) {
+	if pth := viper.ConfigFileUsed(); pth != "" && xbindata.IsStrictConfigFile(pth) {
+		if err := readConfigFile(viper.GetViper(), pth); err != nil {
+			return err
+		}
+	}
+	return unmarshalConfigWith(viper.GetViper(), dest)
+}
+
+// readConfigFile reads the YAML or JSON config file into v, checking the
+// keys and resolving the extends, defaults, include and profiles directives
+// of selected profile. See xbindata.LoadConfigFile.
+func readConfigFile(v *viper.Viper, pth string) (err error) {
+	var (
+		config map[string]interface{}
+		data   []byte
+	)
+	if config, err = xbindata.LoadConfigFile(pth, profile); err != nil {
+		return
+	}
+	if data, err = yaml.Marshal(config); err != nil {
+		return
+	}
+	v.SetConfigType("yaml")
+	return v.ReadConfig(bytes.NewReader(data))
+}
+
+func unmarshalConfigWith(v *viper.Viper, dest interface{}) error {
+	return v.Unmarshal(dest, func(config *mapstructure.DecoderConfig) {
 		oldHook := config.DecodeHook
 		config.DecodeHook = mapstructure.ComposeDecodeHookFunc(oldHook, func(from reflect.Type, to reflect.Type, v interface{}) (interface{}, error) {
 			if to.Kind() == reflect.Struct {
@@ -53,6 +85,8 @@ func unmarshalConfig(dest interface{}) error {
 // buildCmd represents the build command
 var (
 	cfgFile string
+	// profile is the selected config profile. See xbindata.LoadConfigFile.
+	profile string
 
 	buildCmd = &cobra.Command{
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
@@ -61,109 +95,224 @@ var (
 		Use:   "build [PKG...]",
 		Short: "build all or specified PKG from config file",
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			var cfg xbindata.ManyConfig
-			var prod, _ = cmd.Flags().GetBool("prod")
-			if err = unmarshalConfig(&cfg); err != nil {
-				return
-			}
+			var (
+				all, _       = cmd.Flags().GetBool("all")
+				reportPth, _ = cmd.Flags().GetString("report")
+				opts         buildOptions
+				report       = xbindata.NewBuildReport()
+			)
+			opts.prod, _ = cmd.Flags().GetBool("prod")
+			opts.warnOnly, _ = cmd.Flags().GetBool("warn-only")
+			opts.checksums, _ = cmd.Flags().GetBool("checksums")
 
-			if cfgFile, err = filepath.Abs(cfgFile); err != nil {
-				return
-			}
-
-			var cwd string
+			// the interrupt signal cancels the build
+			ctx, cancel := context.WithCancel(xbindata.ContextWithProfile(context.Background(), profile))
+			defer cancel()
+			interrupt := make(chan os.Signal, 1)
+			signal.Notify(interrupt, os.Interrupt)
+			defer signal.Stop(interrupt)
+			go func() {
+				select {
+				case <-interrupt:
+					log.Println("interrupted, cancelling the build...")
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
 
-			if cwd, err = os.Getwd(); err != nil {
-				return
-			} else if cwd != filepath.Dir(cfgFile) {
-				if err = os.Chdir(filepath.Dir(cfgFile)); err != nil {
+			if reportPth != "" && reportPth != xbindata.OutputToStdout {
+				if reportPth, err = filepath.Abs(reportPth); err != nil {
 					return
 				}
 			}
 
-			if err = cfg.Validate(); err != nil {
-				return
-			}
-
 			if len(args) > 0 && (args[0] == "." || args[0] == "."+string(filepath.Separator)) {
 				args = args[1:]
 			}
 
-			if len(args) > 0 {
+			if all {
 				var (
-					embedded []xbindata.ManyConfigEmbedded
-					outline  []xbindata.ManyConfigOutlined
-					accepts  = func(pkg string) bool {
-						for _, arg := range args {
-							if arg == pkg {
-								return true
-							}
-						}
-						return false
-					}
+					cwd   string
+					files []string
 				)
-
-				for _, cfg := range cfg.Outlined {
-					if accepts(cfg.Pkg) {
-						outline = append(outline, cfg)
+				if cwd, err = os.Getwd(); err != nil {
+					return
+				}
+				if files, err = xbindata.FindConfigFiles(cwd); err != nil {
+					return
+				}
+				if files, err = xbindata.ConfigFilesBuildOrder(files); err != nil {
+					return
+				}
+				for _, file := range files {
+					log.Println("######## config file `" + file + "` ########")
+					v := viper.New()
+					v.SetConfigFile(file)
+					if err = readConfigFile(v, file); err != nil {
+						return
+					}
+					if err = buildConfigFile(ctx, v, file, args, opts, report); err != nil {
+						return fmt.Errorf("%s: %v", file, err)
 					}
 				}
-				for _, cfg := range cfg.Embedded {
-					if accepts(cfg.Pkg) {
-						embedded = append(embedded, cfg)
+			} else {
+				if pth := viper.ConfigFileUsed(); pth != "" && xbindata.IsStrictConfigFile(pth) {
+					if err = readConfigFile(viper.GetViper(), pth); err != nil {
+						return
 					}
 				}
-				cfg.Outlined, cfg.Embedded = outline, embedded
-			}
-
-			ctx := context.Background()
-
-			for i, cfg := range cfg.Outlined {
-				log.Println("==== cfg config #"+strconv.Itoa(i)+":", cfg.Pkg, " ====")
-				var (
-					c     *xbindata.Config
-					count int
-				)
-				if c, err = cfg.Config(ctx); err != nil {
-					return fmt.Errorf("cfg #%d [%s]: create config failed: %v", i, cfg.Pkg, err)
+				if cfgFile, err = filepath.Abs(cfgFile); err != nil {
+					return
 				}
-				c.InputProduction = prod
-				if count, err = xbindata.Translate(c); err != nil {
-					return fmt.Errorf("cfg #%d [%s]: translate failed: %v", i, cfg.Pkg, err)
+				if err = buildConfigFile(ctx, viper.GetViper(), cfgFile, args, opts, report); err != nil {
+					return
 				}
-				log.Printf("done with %d assets.\n", count)
 			}
 
-			for i, cfg := range cfg.Embedded {
-				log.Println("==== embeded config #"+strconv.Itoa(i)+":", cfg.Pkg, " ====")
-				var (
-					c     *xbindata.Config
-					count int
-				)
-				if c, err = cfg.Config(ctx); err != nil {
-					return fmt.Errorf("cfg #%d [%s]: create config failed: %v", i, cfg.Pkg, err)
-				}
-				c.InputProduction = prod
-				if count, err = xbindata.Translate(c); err != nil {
-					return fmt.Errorf("cfg #%d [%s]: translate failed: %v", i, cfg.Pkg, err)
-				}
-				log.Printf("done with %d assets.\n", count)
+			if reportPth != "" {
+				return writeReport(report, reportPth)
 			}
-
 			return
 		},
 	}
 )
 
+// buildOptions are the build command options.
+type buildOptions struct {
+	prod, warnOnly, checksums bool
+}
+
+// buildConfigFile builds the configs of file, read into v, or the configs of
+// pkgs if defined.
+func buildConfigFile(ctx context.Context, v *viper.Viper, file string, pkgs []string, opts buildOptions, report *xbindata.BuildReport) (err error) {
+	var cfg xbindata.ManyConfig
+	if err = unmarshalConfigWith(v, &cfg); err != nil {
+		return
+	}
+
+	var cwd string
+
+	if cwd, err = os.Getwd(); err != nil {
+		return
+	} else if cwd != filepath.Dir(file) {
+		if err = os.Chdir(filepath.Dir(file)); err != nil {
+			return
+		}
+		// the next config file of --all is relative to the working directory
+		defer os.Chdir(cwd)
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return
+	}
+
+	if len(pkgs) > 0 {
+		var (
+			embedded []xbindata.ManyConfigEmbedded
+			outline  []xbindata.ManyConfigOutlined
+			accepts  = func(pkg string) bool {
+				for _, arg := range pkgs {
+					if arg == pkg {
+						return true
+					}
+				}
+				return false
+			}
+		)
+
+		for _, cfg := range cfg.Outlined {
+			if accepts(cfg.Pkg) {
+				outline = append(outline, cfg)
+			}
+		}
+		for _, cfg := range cfg.Embedded {
+			if accepts(cfg.Pkg) {
+				embedded = append(embedded, cfg)
+			}
+		}
+		cfg.Outlined, cfg.Embedded = outline, embedded
+	}
+
+	for i, cfg := range cfg.Outlined {
+		log.Println("==== cfg config #"+strconv.Itoa(i)+":", cfg.Pkg, " ====")
+		var (
+			b      *xbindata.Builder
+			result *xbindata.BuildResult
+		)
+		if b, err = cfg.Builder(ctx, opts.options()...); err != nil {
+			return fmt.Errorf("cfg #%d [%s]: create config failed: %v", i, cfg.Pkg, err)
+		}
+		if result, err = b.Build(ctx); err != nil {
+			return fmt.Errorf("cfg #%d [%s]: translate failed: %v", i, cfg.Pkg, err)
+		}
+		report.Add(result)
+		log.Printf("done with %d assets.\n", result.Count)
+	}
+
+	for i, cfg := range cfg.Embedded {
+		log.Println("==== embeded config #"+strconv.Itoa(i)+":", cfg.Pkg, " ====")
+		var (
+			b      *xbindata.Builder
+			result *xbindata.BuildResult
+		)
+		if b, err = cfg.Builder(ctx, opts.options()...); err != nil {
+			return fmt.Errorf("cfg #%d [%s]: create config failed: %v", i, cfg.Pkg, err)
+		}
+		if result, err = b.Build(ctx); err != nil {
+			return fmt.Errorf("cfg #%d [%s]: translate failed: %v", i, cfg.Pkg, err)
+		}
+		report.Add(result)
+		log.Printf("done with %d assets.\n", result.Count)
+	}
+	return
+}
+
+// options returns the builder options.
+func (opts buildOptions) options() (options []xbindata.Option) {
+	if opts.prod {
+		options = append(options, xbindata.WithProduction())
+	}
+	if opts.warnOnly {
+		options = append(options, xbindata.WithBudgetWarnOnly())
+	}
+	if opts.checksums {
+		options = append(options, xbindata.WithChecksums())
+	}
+	return
+}
+
 func init() {
 	rootCmd.AddCommand(buildCmd)
 	flag := buildCmd.Flags()
 	flag.BoolP("program", "P", false, "build outlined and append contents into program")
+	flag.Bool("all", false, "build the "+xbindata.ConfigFileName+" files of current directory tree, in dependency order")
 	flag.Bool("prod", false, "build with production mode")
+	flag.Bool("warn-only", false, "log the size budget violations instead of fail")
+	flag.Bool("checksums", false, "write the SHA256SUMS files of assets and outlined archives")
+	flag.String("report", "", "write the JSON build report into file (`-` to stdout)")
 	flag.StringP("outlined-output-dir", "d", "_assets", "The outlined output root dir")
 	flag.StringP("outlined-output-local-dir", "D", "_assets", "The outlined Local FS root dir")
 
 	buildCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./.xb.yaml)")
+	buildCmd.PersistentFlags().StringVar(&profile, "profile", os.Getenv(xbindata.EnvProfile), "the config profile (default is $"+xbindata.EnvProfile+")")
+}
+
+func writeReport(report *xbindata.BuildReport, pth string) (err error) {
+	if pth == xbindata.OutputToStdout {
+		return report.WriteJSON(os.Stdout)
+	}
+	var f *os.File
+	if f, err = os.Create(pth); err != nil {
+		return
+	}
+	if err = report.WriteJSON(f); err != nil {
+		f.Close()
+		return
+	}
+	if err = f.Close(); err == nil {
+		log.Printf("build report: `%s`\n", pth)
+	}
+	return
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -182,7 +331,7 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		cfgFile = viper.ConfigFileUsed()
 		fmt.Println("Using config file:", cfgFile)
-	} else if !os.IsNotExist(err) {
+	} else if _, notFound := err.(viper.ConfigFileNotFoundError); !notFound && !os.IsNotExist(err) {
 		panic(fmt.Errorf("load config `%v` failed: %v", viper.ConfigFileUsed(), err))
 	}
 }
@@ -156,7 +156,7 @@ Examples:
 					c     *xbindata.Config
 					count int
 				)
-				c, err = cfg.Config(context.Background())
+				c, err = cfg.Config(xbindata.ContextWithProfile(context.Background(), profile))
 				if err != nil {
 					return err
 				}
@@ -0,0 +1,129 @@
+// Copyright © 2019 Moises P. Sena <moisespsena@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/moisespsena-go/xbindata"
+)
+
+var (
+	// configCmd represents the config command
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Config file utilities",
+	}
+
+	// configValidateCmd represents the config validate command
+	configValidateCmd = &cobra.Command{
+		Use:   "validate [FILE]",
+		Short: "Validate the config file (default is ./.xb.yaml)",
+		Long: `Validate the config file (default is ./.xb.yaml).
+
+The unknown keys and the bad value types are reported with file, line and
+column, including the extended and included files. After, the configs are
+loaded as in build command, without build.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			pth := ".xb.yaml"
+			if len(args) == 1 {
+				pth = args[0]
+			}
+			if pth, err = filepath.Abs(pth); err != nil {
+				return
+			}
+			cmd.SilenceUsage = true
+
+			v := viper.New()
+			v.SetConfigFile(pth)
+			if xbindata.IsStrictConfigFile(pth) {
+				if err = readConfigFile(v, pth); err != nil {
+					if errs, ok := err.(xbindata.ConfigErrors); ok {
+						fmt.Fprintln(os.Stderr, errs.Error())
+						return fmt.Errorf("%d errors found", len(errs))
+					}
+					return
+				}
+			} else if err = v.ReadInConfig(); err != nil {
+				return
+			}
+
+			var cfg xbindata.ManyConfig
+			if err = unmarshalConfigWith(v, &cfg); err != nil {
+				return fmt.Errorf("%s: %v", pth, err)
+			}
+
+			if err = os.Chdir(filepath.Dir(pth)); err != nil {
+				return
+			}
+			if err = cfg.Validate(); err != nil {
+				return fmt.Errorf("%s: %v", pth, err)
+			}
+
+			ctx := xbindata.ContextWithProfile(context.Background(), profile)
+			for i, cfg := range cfg.Outlined {
+				if _, err = cfg.Config(ctx); err != nil {
+					return fmt.Errorf("%s: outlined #%d [%s]: %v", pth, i, cfg.Pkg, err)
+				}
+			}
+			for i, cfg := range cfg.Embedded {
+				if _, err = cfg.Config(ctx); err != nil {
+					return fmt.Errorf("%s: embedded #%d [%s]: %v", pth, i, cfg.Pkg, err)
+				}
+			}
+
+			fmt.Println(pth + ": ok")
+			return
+		},
+	}
+
+	// configSchemaCmd represents the config schema command
+	configSchemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			var (
+				out, _ = cmd.Flags().GetString("output")
+				data   []byte
+			)
+			if data, err = json.MarshalIndent(xbindata.ConfigSchema(), "", "  "); err != nil {
+				return
+			}
+			data = append(data, '\n')
+			if out == "" || out == xbindata.OutputToStdout {
+				_, err = os.Stdout.Write(data)
+				return
+			}
+			return ioutil.WriteFile(out, data, 0644)
+		},
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd, configSchemaCmd)
+	configSchemaCmd.Flags().StringP("output", "o", "", "the output file (`-` to stdout)")
+	configValidateCmd.Flags().StringVar(&profile, "profile", os.Getenv(xbindata.EnvProfile), "the config profile (default is $"+xbindata.EnvProfile+")")
+}
@@ -0,0 +1,268 @@
+// Copyright © 2019 Moises P. Sena <moisespsena@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	iocommon "github.com/moisespsena-go/io-common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/moisespsena-go/xbindata"
+	"github.com/moisespsena-go/xbindata/outlined"
+	"github.com/moisespsena-go/xbindata/xbcommon"
+	"github.com/moisespsena-go/xbindata/xbreader"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export PKG_OR_ARCHIVE OUT",
+	Args:  cobra.ExactArgs(2),
+	Short: "Export the assets of package or outlined archive to tar, tar.gz or zip file",
+	Long: `Export the assets of package or outlined archive to tar, tar.gz or zip file.
+
+PKG_OR_ARCHIVE is an outlined archive (.xb or .xb.gz), a program with the
+outlined archive appended (see --program) or a Go package with the generated
+assets. The package is loaded by a temporary program into current module.
+
+OUT is the output file (` + "`-`" + ` to stdout). The format defaults to the OUT
+extension.
+
+Examples:
+	$ ` + prog + ` export _assets/assets.xb.gz assets.zip
+	$ ` + prog + ` export --program ./dist/linux_amd64/program assets.tar.gz
+	$ ` + prog + ` export --format tar ./assets/embedded - | tar -t
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var (
+			src, out   = args[0], args[1]
+			format, _  = cmd.Flags().GetString("format")
+			program, _ = cmd.Flags().GetBool("program")
+			w          io.Writer
+		)
+		if format == "" {
+			if format = xbcommon.ArchiveFormatOf(out); format == "" {
+				format = xbcommon.ArchiveTar
+			}
+		}
+		switch format {
+		case xbcommon.ArchiveTar, xbcommon.ArchiveTarGz, xbcommon.ArchiveZip:
+		default:
+			return fmt.Errorf("unknown format %q", format)
+		}
+
+		if out == xbindata.OutputToStdout {
+			w = os.Stdout
+		} else {
+			var f *os.File
+			if f, err = os.Create(out); err != nil {
+				return
+			}
+			defer func() {
+				if cerr := f.Close(); err == nil {
+					err = cerr
+				}
+				if err != nil {
+					os.Remove(out)
+				}
+			}()
+			w = f
+		}
+
+		if info, serr := os.Stat(src); serr == nil && !info.IsDir() {
+			return exportArchive(w, src, format, program)
+		}
+		return exportPackage(w, src, format)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	flag := exportCmd.Flags()
+	flag.StringP("format", "f", "", "the output format: tar, tar.gz or zip")
+	flag.BoolP("program", "P", false, "the outlined archive is appended to program")
+}
+
+// exportArchive exports the assets of outlined archive file.
+func exportArchive(w io.Writer, pth, format string, program bool) (err error) {
+	var (
+		assets []xbcommon.Asset
+		done   func()
+	)
+	if assets, done, err = openArchive(pth, program); err != nil {
+		return
+	}
+	defer done()
+	return xbcommon.NewAssets(assets...).WriteArchive(w, format)
+}
+
+// openArchive opens the assets of outlined archive file. The done func
+// removes the temporary files.
+func openArchive(pth string, program bool) (assets []xbcommon.Asset, done func(), err error) {
+	done = func() {}
+	if strings.HasSuffix(pth, ".gz") {
+		// outlined.OpenFile uncompress and removes the gz file, so
+		// uncompress it into a temporary file.
+		var tmp *os.File
+		if tmp, err = ioutil.TempFile("", "xb-export"); err != nil {
+			return
+		}
+		defer func() {
+			if err != nil {
+				os.Remove(tmp.Name())
+			}
+		}()
+		done = func() { os.Remove(tmp.Name()) }
+
+		if err = func() (err error) {
+			defer tmp.Close()
+			var f *os.File
+			if f, err = os.Open(pth); err != nil {
+				return
+			}
+			defer f.Close()
+			var gr *gzip.Reader
+			if gr, err = gzip.NewReader(f); err != nil {
+				return
+			}
+			defer gr.Close()
+			_, err = io.Copy(tmp, gr)
+			return
+		}(); err != nil {
+			return nil, nil, errors.Wrapf(err, "uncompress %q", pth)
+		}
+		pth = tmp.Name()
+	}
+
+	var archiv *outlined.Outlined
+	if archiv, err = outlined.OpenFile(pth, program); err != nil {
+		return nil, nil, errors.Wrapf(err, "open outlined %q", pth)
+	}
+
+	var readerFactory outlined.AssetReaderFactory
+	if program {
+		readerFactory = func(start, size int64) func() (iocommon.ReadSeekCloser, error) {
+			return func() (iocommon.ReadSeekCloser, error) {
+				return xbreader.Open(archiv.Path, archiv.StartPos+start, size)
+			}
+		}
+	}
+	return archiv.Assets(readerFactory), done, nil
+}
+
+// exportPackage exports the assets of Go package, running a temporary
+// program into current module.
+func exportPackage(w io.Writer, pkg, format string) (err error) {
+	main := `package main
+
+import (
+	"os"
+
+	pkg "{{PKG}}"
+)
+
+func main() {
+	pkg.Load()
+	if err := pkg.Assets.WriteArchive(os.Stdout, "` + format + `"); err != nil {
+		os.Stderr.WriteString(err.Error() + "\n")
+		os.Exit(1)
+	}
+}
+`
+	return errors.Wrapf(runPackageProgram(w, pkg, main), "export package %q", pkg)
+}
+
+// runPackageProgram builds and runs the temporary program main into current
+// module, replacing the `{{PKG}}` by the import path of pkg. The stdout of
+// program is written into w. The interrupt is forwarded to the program.
+func runPackageProgram(w io.Writer, pkg, main string, buildFlags ...string) (err error) {
+	if strings.HasPrefix(pkg, ".") {
+		var out []byte
+		if out, err = exec.Command("go", "list", pkg).Output(); err != nil {
+			return errors.Wrapf(err, "resolve package %q", pkg)
+		}
+		pkg = strings.TrimSpace(string(out))
+	}
+
+	var dir string
+	if dir, err = ioutil.TempDir(".", "_xbprogram"); err != nil {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	main = strings.Replace(main, "{{PKG}}", pkg, -1)
+	if err = ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte(main), 0644); err != nil {
+		return
+	}
+
+	exe := filepath.Join(dir, "main")
+	if runtime.GOOS == "windows" {
+		exe += ".exe"
+	}
+	args := append(append([]string{"build", "-o", exe}, buildFlags...), "./"+filepath.ToSlash(dir))
+	build := exec.Command("go", args...)
+	build.Stdout, build.Stderr = os.Stderr, os.Stderr
+	if err = build.Run(); err != nil {
+		return
+	}
+
+	// the interrupt stops the program, then the temporary directory is
+	// removed
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	c := exec.Command(exe)
+	c.Stdout, c.Stderr = w, os.Stderr
+	if err = c.Start(); err != nil {
+		return
+	}
+
+	var (
+		done        = make(chan struct{})
+		interrupted = make(chan struct{})
+	)
+	defer close(done)
+	go func() {
+		select {
+		case s := <-sig:
+			close(interrupted)
+			if c.Process.Signal(s) != nil {
+				c.Process.Kill()
+			}
+		case <-done:
+		}
+	}()
+
+	if err = c.Wait(); err != nil {
+		select {
+		case <-interrupted:
+			// the partial output is removed by the caller
+			return errors.New("interrupted")
+		default:
+		}
+	}
+	return
+}
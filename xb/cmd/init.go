@@ -15,11 +15,16 @@
 package cmd
 
 import (
+	"bufio"
+	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/moisespsena-go/path-helpers"
+	"github.com/moisespsena-go/xbindata"
 	"github.com/spf13/cobra"
 )
 
@@ -27,20 +32,38 @@ import (
 var initCmd = &cobra.Command{
 	Use:   "init [DIR...]",
 	Short: "Create config template into DIR",
+	Long: `Create config template into DIR.
+
+With --scan, the DIR is scanned for the assets directories (static,
+templates, public, dist, web and assets) and the go-bindata "go:generate"
+lines, and the config of proposed embedded and outlined packages is written.
+With --interactive, each proposal is confirmed.`,
 
 	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var (
+			scan, _        = cmd.Flags().GetBool("scan")
+			interactive, _ = cmd.Flags().GetBool("interactive")
+			in             = bufio.NewReader(cmd.InOrStdin())
+		)
+
 		if len(args) == 0 {
 			args = append(args, ".")
 		}
 
 		for _, dir := range args {
-			pth := filepath.Join(dir, ".xb.yaml")
+			pth := filepath.Join(dir, xbindata.ConfigFileName)
 			if path_helpers.IsExistingRegularFile(pth) {
 				log.Println("`" + pth + "` ignore")
 				continue
 			}
 
 			log.Println("`" + pth + "` initializing...")
+			config := []byte(configTemplate)
+			if scan {
+				if config, err = scanConfig(dir, interactive, in, cmd.OutOrStdout()); err != nil {
+					return
+				}
+			}
 			if err = path_helpers.MkdirAllIfNotExists(dir); err != nil {
 				return
 			}
@@ -54,7 +77,7 @@ var initCmd = &cobra.Command{
 			}
 			func() {
 				defer f.Close()
-				_, err = f.WriteString(configTemplate)
+				_, err = f.Write(config)
 			}()
 			if err != nil {
 				return
@@ -66,9 +89,69 @@ var initCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().Bool("scan", false, "scan DIR and write the config of detected assets")
+	initCmd.Flags().BoolP("interactive", "i", false, "confirm each proposed config of --scan")
 }
 
-const configTemplate = `# #### EXAMPLE ####
+// scanConfig returns the config of dir proposals, confirmed by user if
+// interactive. Without proposals, returns the config template.
+func scanConfig(dir string, interactive bool, in *bufio.Reader, out io.Writer) (config []byte, err error) {
+	var proposals []*xbindata.ScanProposal
+	var warnings []string
+	if proposals, warnings, err = xbindata.ScanProject(dir); err != nil {
+		return
+	}
+	for _, w := range warnings {
+		log.Println("scan:", w)
+	}
+
+	if interactive {
+		var confirmed []*xbindata.ScanProposal
+		for _, p := range proposals {
+			fmt.Fprintf(out, "%s\nAdd it? [Y/n] ", p)
+			var answer string
+			if answer, err = in.ReadString('\n'); err != nil && err != io.EOF {
+				return
+			}
+			err = nil
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "", "y", "yes":
+				confirmed = append(confirmed, p)
+			}
+		}
+		proposals = confirmed
+	} else {
+		for _, p := range proposals {
+			log.Println("scan:", p)
+		}
+	}
+
+	if len(proposals) == 0 {
+		log.Println("scan: no assets found, writing the config template")
+		return []byte(configTemplate), nil
+	}
+	config = xbindata.MarshalScanProposals(proposals)
+	if err = xbindata.CheckConfig(filepath.Join(dir, xbindata.ConfigFileName), config); err != nil {
+		return nil, fmt.Errorf("scan: bad proposed config: %v", err)
+	}
+	return
+}
+
+const configTemplate = `# yaml-language-server: $schema=` + xbindata.ConfigSchemaID + `
+# #### EXAMPLE ####
+# # the base files (relative to this file), merged under this file
+# extends: ../xb.base.yaml
+# # the defaults of each embedded and outlined config of this file
+# defaults:
+#   fs: true
+#   # the overrides of profile selected by "xb build --profile NAME" or
+#   # XB_PROFILE, accepted by embedded, outlined and input configs too
+#   profiles:
+#     prod:
+#       no_compress: false
+# # the files (or glob patterns) whose configs are appended to this file
+# include:
+#   - services/*/xb.assets.yaml
 # embedded:
 #   - pkg: assets/embeded
 #     prefix: assets/program/assets
@@ -77,6 +160,45 @@ const configTemplate = `# #### EXAMPLE ####
 #     inputs:
 #       - path: assets/program/assets
 #         recursive: true
+#         # symlinks: follow (default), preserve, skip or error
+#         symlinks: preserve
+#       # zip, tar, tar.gz and tgz archives are walked as directories
+#       - path: vendor/fonts.zip
+#         subdir: fonts
+#         prefix: _
+#         ns: fonts
+#         recursive: true
+#       # the go: paths are resolved from module build list or, with
+#       # version, from module cache
+#       - path: go:example.com/ui@v1.2.3/assets
+#         prefix: _
+#         ns: ui
+#         recursive: true
+#       # the entries are walked by in-process walker, registered with
+#       # xbindata.RegisterWalker, or by the INPUT/.xbwalk program
+#       - path: generated
+#         walker: my_walker
+#     budget:
+#       max_total_size: 20MB
+#       max_file_size: 2MB
+#       max_files: 500
+#       name_spaces:
+#         videos:
+#           max_total_size: 100MB
+#     content_types: true
+#     metadata_sidecars: true
+#     # writes the assets_xb_test.go test of generated package
+#     self_test: true
+#     # splits the assets data into packages of about 32MB of Go source
+#     shard_size: 32MB
+#     metadata:
+#       - glob: "**.css"
+#         values:
+#           header.Cache-Control: max-age=3600
+#     collisions:
+#       strict: true
+#       case_fold: true
+#       unicode: true
 # 
 # outlined:
 #   - pkg: assets/program
@@ -84,13 +206,11 @@ const configTemplate = `# #### EXAMPLE ####
 #     prefix: _
 #     fs: true
 #     hybrid: false
-#     compressed: true
 # 
 #   - pkg: assets/outlined
 #     prefix: assets/program/assets
 #     fs: true
 #     hybrid: true
-#     compressed: true
 #     inputs:
 #       - path: assets/program/assets
 #         recursive: true
@@ -100,7 +220,7 @@ const configTemplate = `# #### EXAMPLE ####
 #     prefix: _
 #     fs: true
 #     hybrid: true
-#     compressed: false
+#     no_compress: true
 #     inputs:
 #       - path: inputs/a
 #         prefix: _
@@ -0,0 +1,146 @@
+// Copyright © 2019 Moises P. Sena <moisespsena@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/gobwas/glob"
+	"github.com/spf13/cobra"
+
+	"github.com/moisespsena-go/xbindata"
+)
+
+// packCmd represents the pack command
+var packCmd = &cobra.Command{
+	Use:   "pack [FLAGS] INPUT...",
+	Short: "Pack the INPUT directories without config file",
+	Long: `Pack the INPUT directories without config file.
+
+The flags are compatible with go-bindata: the single dash long flags
+(` + "`-pkg`" + `) are accepted. The INPUT with ` + "`/...`" + ` suffix is walked
+recursively.
+
+Examples:
+	$ ` + prog + ` pack -o assets/assets.go -pkg assets -prefix data data/...
+	$ ` + prog + ` pack -outlined -api assets/assets.go -o _assets/assets.xb -pkg assets data/...
+
+	//go:generate xb pack -pkg assets -nomemcopy -fs data/...
+`,
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var (
+			c     = xbindata.NewConfig()
+			flags = flag.NewFlagSet(prog+" pack", flag.ContinueOnError)
+			tags  string
+			shard string
+		)
+		flags.SetOutput(cmd.OutOrStderr())
+		flags.Usage = func() {
+			fmt.Fprintf(flags.Output(), "%s\n\nUsage:\n  %s\n\nFlags:\n", cmd.Long, cmd.UseLine())
+			flags.PrintDefaults()
+		}
+
+		flags.StringVar(&c.Output, "o", c.Output, "Optional name of the output file to be generated.")
+		flags.StringVar(&c.Package, "pkg", c.Package, "Package name to use in the generated code.")
+		flags.StringVar(&c.Prefix, "prefix", c.Prefix, "Optional path prefix to strip off asset names.")
+		flags.StringVar(&tags, "tags", "", "Optional set of build tags to include.")
+		flags.BoolVar(&c.NoCompress, "nocompress", c.NoCompress, "Assets will *not* be GZIP compressed when this flag is specified.")
+		flags.BoolVar(&c.NoMemCopy, "nomemcopy", c.NoMemCopy, "Use a .rodata hack to get rid of unnecessary memcopies. Refer to the documentation to see what implications this carries.")
+		flags.BoolVar(&c.NoMetadata, "nometadata", c.NoMetadata, "Assets will not preserve size, mode, and modtime info.")
+		flags.BoolVar(&c.Debug, "debug", c.Debug, "Do not embed the assets, but provide the embedding API. Contents will still be loaded from disk.")
+		flags.BoolVar(&c.Dev, "dev", c.Dev, "Similar to debug, but does not emit absolute paths. Expects a rootDir variable to already exist in the generated code's package.")
+		flags.UintVar(&c.Mode, "mode", c.Mode, "Optional file mode override for all files.")
+		flags.Int64Var(&c.ModTime, "modtime", c.ModTime, "Optional modification unix timestamp override for all files.")
+		flags.BoolVar(&c.FileSystem, "fs", c.FileSystem, "Generate the file system API.")
+		flags.BoolVar(&c.Hybrid, "hybrid", c.Hybrid, "Generate the dev mode local file system too, enabled by the `dev` build tag.")
+		flags.BoolVar(&c.NoAutoLoad, "noautoload", c.NoAutoLoad, "Do not load the assets on package init.")
+		flags.BoolVar(&c.Outlined, "outlined", c.Outlined, "Store the assets into the outlined archive (-o) instead of Go source.")
+		flags.StringVar(&c.OutlinedApi, "api", c.OutlinedApi, "The Go source file of outlined API.")
+		flags.BoolVar(&c.OutlinedProgram, "program", c.OutlinedProgram, "Append the outlined archive to the program file (-o).")
+		flags.BoolVar(&c.InputProduction, "prod", c.InputProduction, "Walk the inputs in production mode.")
+		flags.BoolVar(&c.IntegritySHA384, "integrity", c.IntegritySHA384, "Generate the Subresource Integrity values of assets.")
+		flags.BoolVar(&c.Checksums, "checksums", c.Checksums, "Write the SHA256SUMS files of assets and outlined archive.")
+		flags.BoolVar(&c.SelfTest, "self-test", c.SelfTest, "Write the test of generated package.")
+		flags.BoolVar(&c.ContentTypes, "content-types", c.ContentTypes, "Detect the content types of assets.")
+		flags.BoolVar(&c.MetadataSidecars, "metadata-sidecars", c.MetadataSidecars, "Read the asset metadata from *"+xbindata.MetadataSidecarSuffix+" files.")
+		flags.BoolVar(&c.PreserveDirs, "preserve-dirs", c.PreserveDirs, "Store the directory entries, with mode and modification time.")
+		flags.StringVar(&shard, "shard-size", "", "Split the embedded assets data into packages of about this size, example: 32MB.")
+		flags.StringVar(&c.Accessors, "accessors", c.Accessors, "Generate the typed asset accessors: const or tree.")
+		flags.StringVar(&c.HashedNames, "hashed-names", c.HashedNames, "Add the content hash into asset names: add or only.")
+		flags.IntVar(&c.HashedNamesLength, "hashed-names-length", c.HashedNamesLength, "The hex length of content hash in hashed names.")
+		flags.StringVar(&c.HashedNamesManifest, "hashed-names-manifest", c.HashedNamesManifest, "The hashed names manifest file.")
+		flags.Func("ignore", "Regex pattern to ignore (accepts many).", func(value string) error {
+			re, err := regexp.Compile(value)
+			if err == nil {
+				c.Ignore = append(c.Ignore, re)
+			}
+			return err
+		})
+		flags.Func("ignore-glob", "Glob pattern to ignore (accepts many).", func(value string) error {
+			g, err := glob.Compile(value)
+			if err == nil {
+				c.IgnoreGlob = append(c.IgnoreGlob, g)
+			}
+			return err
+		})
+
+		if err = flags.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil
+			}
+			return
+		}
+		cmd.SilenceUsage = true
+
+		if flags.NArg() == 0 {
+			return fmt.Errorf("missing INPUT")
+		}
+		for _, pth := range flags.Args() {
+			c.Input = append(c.Input, parseInput(pth))
+		}
+
+		// as go-bindata, the tags are one build constraint line
+		if tags = strings.TrimSpace(tags); tags != "" {
+			c.Tags = []string{tags}
+		}
+		if shard != "" {
+			var size uint64
+			if size, err = humanize.ParseBytes(shard); err != nil {
+				return fmt.Errorf("invalid shard size: %v", err)
+			}
+			c.ShardSize = int64(size)
+		}
+		if c.Outlined && c.Output == xbindata.DefaultOutput {
+			c.Output = ""
+		}
+
+		var count int
+		if count, err = xbindata.Translate(c); err != nil {
+			return
+		}
+		log.Printf("done with %d assets.\n", count)
+		return
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(packCmd)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xb-pack")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	data := filepath.Join(dir, "data")
+	for name, content := range map[string]string{
+		"a.txt":      "a",
+		"sub/b.txt":  "b",
+		"sub/c.log":  "c",
+		"skip/d.txt": "d",
+	} {
+		pth := filepath.Join(data, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(pth, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := filepath.Join(dir, "assets", "assets.go")
+	if err = packCmd.RunE(packCmd, []string{
+		"-o", out,
+		"-pkg", "assets",
+		"-tags", "a b",
+		"-nocompress",
+		"-prefix", data,
+		"-ignore", `\.log$`,
+		"-ignore-glob", "**/skip/*",
+		data + "/...",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(b)
+	for _, s := range []string{"\n// +build a b\n", "\npackage assets\n", `"a.txt"`, `"sub/b.txt"`} {
+		if !strings.Contains(src, s) {
+			t.Errorf("%q not found in output", s)
+		}
+	}
+	for _, s := range []string{"// +build a\n", `"sub/c.log"`, `"skip/d.txt"`, `"` + data} {
+		if strings.Contains(src, s) {
+			t.Errorf("unexpected %q in output", s)
+		}
+	}
+}
+
+func TestPackCmdBadIgnore(t *testing.T) {
+	for _, flag := range []string{"-ignore", "-ignore-glob"} {
+		packCmd.SetOut(ioutil.Discard)
+		if err := packCmd.RunE(packCmd, []string{flag, "[", "data/..."}); err == nil {
+			t.Errorf("%s: expected error", flag)
+		}
+	}
+}
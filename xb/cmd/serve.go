@@ -0,0 +1,162 @@
+// Copyright © 2019 Moises P. Sena <moisespsena@gmail.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+	"github.com/moisespsena-go/xbindata/xbfs"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve PKG_OR_ARCHIVE",
+	Args:  cobra.ExactArgs(1),
+	Short: "Serve the assets of package or outlined archive over HTTP",
+	Long: `Serve the assets of package or outlined archive over HTTP, for checking
+exactly what ships.
+
+PKG_OR_ARCHIVE is an outlined archive (.xb or .xb.gz), a program with the
+outlined archive appended (see --program) or a Go package with the generated
+assets. The package is served by a temporary program into current module,
+from the local files if --dev (the ` + "`dev`" + ` build tag of hybrid packages).
+
+The assets are served with the same response headers of the production
+xbfs.FileSystem.ServeHTTP. The directories are listed (JSON if requested by
+the ` + "`Accept: application/json`" + ` header), the JSON metadata of each asset is
+served at ` + xbfs.BrowseMetaPath + `NAME and the change events of --dev
+packages at ` + xbfs.BrowseEventsPath + ` (Server-Sent Events).
+
+Examples:
+	$ ` + prog + ` serve _assets/assets.xb.gz
+	$ ` + prog + ` serve --program ./dist/linux_amd64/program
+	$ ` + prog + ` serve --dev --addr :8000 ./assets
+	$ curl localhost:8080` + xbfs.BrowseMetaPath + `index.html
+`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var (
+			src        = args[0]
+			addr, _    = cmd.Flags().GetString("addr")
+			program, _ = cmd.Flags().GetBool("program")
+			dev, _     = cmd.Flags().GetBool("dev")
+		)
+
+		if info, serr := os.Stat(src); serr == nil && !info.IsDir() {
+			if dev {
+				return errors.New("--dev requires a package")
+			}
+			return serveArchive(addr, src, program)
+		}
+		return servePackage(addr, src, dev)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	flag := serveCmd.Flags()
+	flag.StringP("addr", "a", "localhost:8080", "the listen address")
+	flag.BoolP("program", "P", false, "the outlined archive is appended to program")
+	flag.Bool("dev", false, "serve the package local files, with the `dev` build tag")
+}
+
+// serveArchive serves the assets of outlined archive file.
+func serveArchive(addr, pth string, program bool) (err error) {
+	var (
+		assets []xbcommon.Asset
+		done   func()
+	)
+	if assets, done, err = openArchive(pth, program); err != nil {
+		return
+	}
+	defer done()
+
+	// removes the temporary files on interrupt
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	defer signal.Stop(sig)
+
+	var (
+		fs     = xbfs.NewFileSystem(xbcommon.NewAssets(assets...).Root())
+		server = &http.Server{Addr: addr, Handler: xbfs.NewBrowseHandler(fs)}
+	)
+	go func() {
+		<-sig
+		server.Close()
+	}()
+
+	log.Printf("serving %q at http://%s/", pth, addr)
+	if err = server.ListenAndServe(); err == http.ErrServerClosed {
+		err = nil
+	}
+	return
+}
+
+// servePackage serves the assets of Go package, running a temporary
+// program into current module.
+func servePackage(addr, pkg string, dev bool) (err error) {
+	main := `package main
+
+import (
+	"log"
+	"net/http"
+
+	pkg "{{PKG}}"
+	"github.com/moisespsena-go/xbindata/xbfs"
+`
+	if dev {
+		main += `)
+
+func fileSystem() *xbfs.FileSystem {
+	return pkg.FS().(*xbfs.FileSystem)
+}
+`
+	} else {
+		main += `	bc "github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+func fileSystem() *xbfs.FileSystem {
+	pkg.Load()
+	var assets *bc.Assets
+	// the embedded packages Assets is a pointer
+	switch a := interface{}(&pkg.Assets).(type) {
+	case **bc.Assets:
+		assets = *a
+	case *bc.Assets:
+		assets = a
+	}
+	return xbfs.NewFileSystem(assets.Root()).SetNameResolver(assets)
+}
+`
+	}
+	main += `
+func main() {
+	log.Printf("serving %q at http://%s/", "{{PKG}}", ` + strconv.Quote(addr) + `)
+	log.Fatal(http.ListenAndServe(` + strconv.Quote(addr) + `, xbfs.NewBrowseHandler(fileSystem())))
+}
+`
+	var buildFlags []string
+	if dev {
+		buildFlags = append(buildFlags, "-tags", "dev")
+	}
+	return errors.Wrapf(runPackageProgram(os.Stdout, pkg, main, buildFlags...), "serve package %q", pkg)
+}
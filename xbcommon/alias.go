@@ -0,0 +1,56 @@
+package xbcommon
+
+import (
+	"path"
+
+	path_helpers "github.com/moisespsena-go/path-helpers"
+)
+
+// Alias is an asset published under another name.
+type Alias struct {
+	Asset
+	nodeCommon
+	path string
+	name string
+}
+
+func NewAlias(pth string, asset Asset) *Alias {
+	return &Alias{Asset: asset, path: pth, name: path.Base(pth)}
+}
+
+// Original returns the aliased asset.
+func (a *Alias) Original() Asset {
+	return a.Asset
+}
+
+func (a *Alias) Path() string {
+	return a.path
+}
+
+func (a *Alias) Name() string {
+	return a.name
+}
+
+func (a *Alias) Depth() int {
+	return a.nodeCommon.Depth()
+}
+
+func (a *Alias) Index() int {
+	return a.nodeCommon.Index()
+}
+
+func (a *Alias) IsFirst() bool {
+	return a.nodeCommon.IsFirst()
+}
+
+func (a *Alias) IsLast() bool {
+	return a.nodeCommon.IsLast()
+}
+
+// Restore restores an asset under the given directory.
+func (a *Alias) Restore(baseDir string) (err error) {
+	if err = path_helpers.MkdirAllIfNotExists(FilePath(baseDir, path.Dir(a.path))); err != nil {
+		return
+	}
+	return a.Save(FilePath(baseDir, a.path))
+}
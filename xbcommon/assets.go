@@ -27,15 +27,45 @@ type Assets struct {
 	mu      sync.Mutex
 	Factory func() (assets map[string]Asset, err error)
 
+	hashedNames   map[string]string
+	originalNames map[string]string
+	hashedOnly    bool
+
+	// dirs are the directory entries.
+	dirs map[string]os.FileInfo
+
+	cache *DataCache
+
 	local.LocalSourcesAttribute
 }
 
-func NewAssets(assets ...Asset) *Assets {
+func NewAssets(asset ...Asset) (assets *Assets) {
 	var assetsMap = make(map[string]Asset)
-	for _, asset := range assets {
+	for _, asset := range asset {
 		assetsMap[asset.Path()] = asset
 	}
-	return &Assets{Assets: &assetsMap}
+	assets = &Assets{Assets: &assetsMap}
+	assets.splitDirs()
+	return assets
+}
+
+// splitDirs moves the directory entries from assets map to dirs.
+func (assets *Assets) splitDirs() {
+	for name, asset := range *assets.Assets {
+		if asset.IsDir() {
+			if assets.dirs == nil {
+				assets.dirs = map[string]os.FileInfo{}
+			}
+			assets.dirs[name] = asset
+			delete(*assets.Assets, name)
+		}
+	}
+}
+
+// Dirs returns the directory entries.
+func (assets *Assets) Dirs() map[string]os.FileInfo {
+	assets.check()
+	return assets.dirs
 }
 
 func (assets *Assets) check() {
@@ -51,6 +81,8 @@ func (assets *Assets) check() {
 		}
 		assets.Assets = &data
 		assets.Factory = nil
+		assets.splitDirs()
+		assets.applyHashedNames()
 	}
 }
 
@@ -66,6 +98,9 @@ func (assets *Assets) Root() NodeDir {
 				pathList := strings.Split(name, "/")
 				tree.Add(pathList, asset)
 			}
+			for name, info := range assets.dirs {
+				tree.AddDir(strings.Split(name, "/"), info)
+			}
 			assets.root = tree.Node().(NodeDir)
 			tree = nil
 		}
@@ -94,7 +129,7 @@ func (assets *Assets) GetC(ctx context.Context, name string) (asset Asset, ok bo
 			} else if !info.IsDir() {
 				localAsset := assetfs.NewRealFileInfo(assetfsapi.OsFileInfoToBasic(name, info), info.Path())
 				if asset, ok = (*assets.Assets)[name]; ok {
-					asset = &LocalFile{RealFileInfo: localAsset, nodeCommon: asset.(*File).nodeCommon}
+					asset = &LocalFile{RealFileInfo: localAsset, asset: asset, nodeCommon: asset.(*File).nodeCommon}
 					return
 				}
 				ok = true
@@ -104,7 +139,13 @@ func (assets *Assets) GetC(ctx context.Context, name string) (asset Asset, ok bo
 		}
 	}
 
-	asset, ok = (*assets.Assets)[name]
+	if asset, ok = (*assets.Assets)[name]; !ok {
+		if alt := assets.HashedName(name); alt != name {
+			asset, ok = (*assets.Assets)[alt]
+		} else if alt = assets.Original(name); alt != name {
+			asset, ok = (*assets.Assets)[alt]
+		}
+	}
 	return
 }
 
@@ -133,6 +174,35 @@ func (assets *Assets) Names() []string {
 	return names
 }
 
+// SetCache sets the data cache of assets files. Must be set before the
+// concurrent use of assets. See DataCache.
+func (assets *Assets) SetCache(cache *DataCache) *Assets {
+	assets.check()
+	assets.mu.Lock()
+	defer assets.mu.Unlock()
+	assets.cache = cache
+	for _, asset := range *assets.Assets {
+		if f, ok := asset.(*File); ok {
+			f.SetCache(cache)
+		}
+	}
+	return assets
+}
+
+// Cache returns the data cache of assets, if any.
+func (assets *Assets) Cache() *DataCache {
+	return assets.cache
+}
+
+// Preload warms up the data cache with the assets matching any of glob
+// patterns. See DataCache.Preload.
+func (assets *Assets) Preload(patterns ...string) (count int, err error) {
+	if assets.cache == nil {
+		return 0, fmt.Errorf("assets without data cache")
+	}
+	return assets.cache.Preload(assets.Root(), patterns...)
+}
+
 // RestoreAsset restores an asset under the given directory.
 func (assets *Assets) RestoreAsset(dir, name string) (err error) {
 	assets.check()
@@ -149,12 +219,11 @@ func (assets *Assets) RestoreAsset(dir, name string) (err error) {
 // RestoreAssets restores an asset under the given directory recursively.
 func (assets *Assets) RestoreDir(dir, name string) (err error) {
 	assets.check()
-	var n Node
-	if n, err = assets.Root().GetDir(name); err != nil {
-		return
-	}
-	if n.IsDir() {
-		return oscommon.ErrNotFile(name)
+	var n = assets.Root()
+	if name != "" && name != "." {
+		if n, err = n.GetDir(name); err != nil {
+			return
+		}
 	}
-	return n.(Asset).Restore(dir)
+	return n.Restore(dir)
 }
@@ -0,0 +1,161 @@
+package xbcommon
+
+import (
+	"container/list"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/gobwas/glob"
+	"github.com/moisespsena-go/io-common"
+)
+
+// DataCache is a bounded LRU cache of the decompressed data of files, safe
+// for concurrent use. The least recently used data are evicted when the
+// cached bytes exceed the budget. The files with cache read the data from
+// the cache, see File.SetCache, Assets.SetCache and
+// xbfs.FileSystem.SetCache.
+//
+// The cached data are shared by the readers, so the data returned by
+// File.Data must not be modified.
+type DataCache struct {
+	maxBytes int64
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[*File]*list.Element
+	stats   DataCacheStats
+}
+
+// DataCacheStats are the cache statistics.
+type DataCacheStats struct {
+	Hits, Misses, Evictions uint64
+	// Entries is the count of cached files.
+	Entries int
+	// Bytes is the size of cached data and MaxBytes the budget.
+	Bytes, MaxBytes int64
+}
+
+func (s DataCacheStats) String() string {
+	return fmt.Sprintf("hits=%d misses=%d evictions=%d entries=%d bytes=%d/%d",
+		s.Hits, s.Misses, s.Evictions, s.Entries, s.Bytes, s.MaxBytes)
+}
+
+type dataCacheEntry struct {
+	file *File
+	data []byte
+}
+
+// NewDataCache returns a new cache with the maxBytes budget.
+func NewDataCache(maxBytes int64) *DataCache {
+	return &DataCache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		entries:  map[*File]*list.Element{},
+	}
+}
+
+// Data returns the cached data of file, reading and caching it on miss.
+// The data larger than the budget is not cached.
+func (c *DataCache) Data(f *File) (data []byte, err error) {
+	c.mu.Lock()
+	if e, ok := c.entries[f]; ok {
+		c.ll.MoveToFront(e)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return e.Value.(*dataCacheEntry).data, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	if data, err = f.readData(); err != nil {
+		return
+	}
+	c.add(f, data)
+	return
+}
+
+func (c *DataCache) add(f *File, data []byte) {
+	size := int64(len(data))
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[f]; ok {
+		// read by concurrent miss
+		c.ll.MoveToFront(e)
+		return
+	}
+	c.entries[f] = c.ll.PushFront(&dataCacheEntry{f, data})
+	c.stats.Bytes += size
+	for c.stats.Bytes > c.maxBytes {
+		c.remove(c.ll.Back())
+		c.stats.Evictions++
+	}
+}
+
+func (c *DataCache) remove(e *list.Element) {
+	entry := c.ll.Remove(e).(*dataCacheEntry)
+	delete(c.entries, entry.file)
+	c.stats.Bytes -= int64(len(entry.data))
+}
+
+// Stats returns the cache statistics.
+func (c *DataCache) Stats() DataCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries, stats.MaxBytes = c.ll.Len(), c.maxBytes
+	return stats
+}
+
+// Purge removes all cached data. The statistics are kept.
+func (c *DataCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ll.Init()
+	c.entries = map[*File]*list.Element{}
+	c.stats.Bytes = 0
+}
+
+// Preload warms up the cache with the files of root matching any of glob
+// patterns, relative to root. The `*` does not matches the `/` separator,
+// use `**` to matches any sub directory. Returns the count of preloaded
+// files.
+func (c *DataCache) Preload(root NodeDir, patterns ...string) (count int, err error) {
+	var globs = make([]glob.Glob, len(patterns))
+	for i, pattern := range patterns {
+		if globs[i], err = glob.Compile(pattern, '/'); err != nil {
+			return 0, fmt.Errorf("invalid preload glob pattern %q: %v", pattern, err)
+		}
+	}
+	err = root.Walk(func(dir, name string, n Node, _ interface{}) (_ interface{}, err error) {
+		f, ok := n.(*File)
+		if !ok {
+			return
+		}
+		pth := path.Join(dir, name)
+		for _, g := range globs {
+			if g.Match(pth) {
+				if _, err = c.Data(f); err != nil {
+					return nil, fmt.Errorf("preload %q: %v", pth, err)
+				}
+				count++
+				break
+			}
+		}
+		return
+	})
+	return
+}
+
+// reader returns the reader of cached data of file.
+func (c *DataCache) reader(f *File) (iocommon.ReadSeekCloser, error) {
+	data, err := c.Data(f)
+	if err != nil {
+		return nil, err
+	}
+	return iocommon.NewBytesReadCloser(data), nil
+}
@@ -0,0 +1,85 @@
+package xbcommon
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	iocommon "github.com/moisespsena-go/io-common"
+)
+
+func TestDataCache(t *testing.T) {
+	var reads int32
+	newFile := func(name, data string) *File {
+		info := NewFileInfo(name, int64(len(data)), os.FileMode(0644), time.Time{}, time.Time{})
+		return NewFile(info, func() (iocommon.ReadSeekCloser, error) {
+			atomic.AddInt32(&reads, 1)
+			return iocommon.NewBytesReadCloser([]byte(data)), nil
+		}, nil)
+	}
+	var (
+		a     = newFile("a.txt", "aaaa")
+		b     = newFile("d/b.txt", "bbbb")
+		c     = newFile("d/c.html", "cccc")
+		big   = newFile("big", "0123456789")
+		cache = NewDataCache(8)
+	)
+	NewAssets(a, b, c, big).SetCache(cache)
+
+	for i := 0; i < 3; i++ {
+		if data, err := a.DataS(); err != nil || data != "aaaa" {
+			t.Fatalf("bad data %q: %v", data, err)
+		}
+	}
+	if reads != 1 {
+		t.Errorf("have %d reads, want 1", reads)
+	}
+
+	// the cached reader seeks
+	r, _ := a.Reader()
+	r.Seek(2, 0)
+	if data, _ := ioutil.ReadAll(r); string(data) != "aa" {
+		t.Errorf("bad seeked data %q", data)
+	}
+
+	// c evicts b, the least recently used, and big is not cached
+	b.Data()
+	a.Data()
+	c.Data()
+	big.Data()
+	if s := cache.Stats(); s.Hits != 4 || s.Misses != 4 || s.Evictions != 1 || s.Entries != 2 || s.Bytes != 8 {
+		t.Errorf("bad stats %v", s)
+	}
+	if _, ok := cache.entries[b]; ok {
+		t.Errorf("b not evicted")
+	}
+
+	cache.Purge()
+	if n, err := cache.Preload(NewAssets(a, b, c).Root(), "d/*.txt", "*.html", "**.html"); err != nil || n != 2 {
+		t.Errorf("bad preload count %d: %v", n, err)
+	}
+	if _, err := cache.Preload(NewAssets(a).Root(), "["); err == nil || !strings.Contains(err.Error(), "invalid preload glob") {
+		t.Errorf("bad preload error %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, f := range []*File{a, b, c, big} {
+				if _, err := f.Data(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if s := cache.Stats(); s.Bytes > 8 || s.Entries > 2 {
+		t.Errorf("budget exceeded %v", s)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	oscommon "github.com/moisespsena-go/os-common"
 )
@@ -36,7 +37,7 @@ func NewDir(depth int, pth string, children map[string]Node) *Dir {
 		c.setLast(i == l)
 	}
 
-	d := &Dir{DirInfo: &DirInfo{pth, path.Base(pth)}, children: children, sorted: names}
+	d := &Dir{DirInfo: NewDirInfo(pth, 0, time.Time{}), children: children, sorted: names}
 	d.setDepth(depth)
 	return d
 }
@@ -175,12 +176,40 @@ func (t *Dir) Save(dest string) (err error) {
 	})
 }
 
-// Restore restores an asset under the given directory.
+// Restore restores an asset under the given directory. The directories
+// are created writable by the owner and, if have stored entry (the
+// preserve dirs option), set to the stored mode and modification time after
+// the children creation. The other directories are kept as is.
 func (t *Dir) Restore(baseDir string) (err error) {
-	return t.Walk(func(dir, name string, n Node, _ interface{}) (interface{}, error) {
+	type restored struct {
+		dir NodeDir
+		pth string
+	}
+	var dirs []restored
+	if err = t.Walk(func(dir, name string, n Node, _ interface{}) (interface{}, error) {
+		pth := FilePath(baseDir, dir, name)
 		if !n.IsDir() {
-			return nil, n.(Asset).Save(FilePath(baseDir, dir, n.Name()))
+			return nil, n.(Asset).Save(pth)
 		}
-		return nil, nil
-	})
+		if d, ok := n.(*Dir); ok && d.stored() {
+			dirs = append(dirs, restored{d, pth})
+		}
+		return nil, os.MkdirAll(pth, n.Mode().Perm()|0700)
+	}); err != nil {
+		return
+	}
+
+	// sets the metadata after the children creation, on reverse order
+	for i := len(dirs) - 1; i >= 0; i-- {
+		d := dirs[i]
+		if err = os.Chmod(d.pth, d.dir.Mode().Perm()); err != nil {
+			return
+		}
+		if modTime := d.dir.ModTime(); !modTime.IsZero() {
+			if err = os.Chtimes(d.pth, modTime, modTime); err != nil {
+				return
+			}
+		}
+	}
+	return
 }
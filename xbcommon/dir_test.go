@@ -0,0 +1,120 @@
+package xbcommon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	iocommon "github.com/moisespsena-go/io-common"
+)
+
+func TestAssetsDirEntries(t *testing.T) {
+	modTime := time.Unix(1577934245, 0)
+	assets := NewAssets(
+		NewFile(NewFileInfo("a/b.txt", 1, os.FileMode(0644), time.Time{}, time.Time{}), func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte("b")), nil
+		}, nil),
+		NewFile(NewFileInfo("a", 0, os.ModeDir|0700, modTime, time.Time{}), nil, nil),
+		NewFile(NewFileInfo("empty/deep", 0, os.ModeDir|0750, modTime, time.Time{}), nil, nil),
+	)
+
+	if names := assets.Names(); len(names) != 1 || names[0] != "a/b.txt" {
+		t.Errorf("directory entries listed as assets: %v", names)
+	}
+
+	for name, mode := range map[string]os.FileMode{"a": 0700, "empty": 0755, "empty/deep": 0750} {
+		d, err := assets.Root().GetDir(name)
+		if err != nil {
+			t.Errorf("dir %q not found: %v", name, err)
+			continue
+		}
+		if d.Mode() != os.ModeDir|mode {
+			t.Errorf("dir %q: bad mode %v", name, d.Mode())
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "xbcommon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = assets.RestoreDir(dir, "."); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filepath.Join(dir, "empty", "deep"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0750 || !info.ModTime().Equal(modTime) {
+		t.Errorf("bad restored dir mode %v or mod time %v", info.Mode(), info.ModTime())
+	}
+}
+
+func TestDirRestoreReadOnly(t *testing.T) {
+	assets := NewAssets(
+		NewFile(NewFileInfo("ro/sub/c.txt", 1, os.FileMode(0444), time.Time{}, time.Time{}), func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte("c")), nil
+		}, nil),
+		NewFile(NewFileInfo("ro", 0, os.ModeDir|0555, time.Time{}, time.Time{}), nil, nil),
+		NewFile(NewFileInfo("ro/sub", 0, os.ModeDir|0500, time.Time{}, time.Time{}), nil, nil),
+	)
+
+	dir, err := ioutil.TempDir("", "xbcommon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer filepath.Walk(dir, func(pth string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			os.Chmod(pth, 0700)
+		}
+		return nil
+	})
+
+	if err = assets.RestoreDir(dir, "."); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "ro", "sub", "c.txt")); err != nil || string(data) != "c" {
+		t.Errorf("bad restored data %q: %v", data, err)
+	}
+	for name, mode := range map[string]os.FileMode{"ro": 0555, "ro/sub": 0500} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Mode().Perm() != mode {
+			t.Errorf("bad restored dir %q mode: %v", name, err)
+		}
+	}
+}
+
+func TestDirRestoreExisting(t *testing.T) {
+	assets := NewAssets(
+		NewFile(NewFileInfo("priv/a.txt", 1, os.FileMode(0644), time.Time{}, time.Time{}), func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte("a")), nil
+		}, nil),
+	)
+
+	dir, err := ioutil.TempDir("", "xbcommon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	modTime := time.Unix(1577934245, 0)
+	priv := filepath.Join(dir, "priv")
+	if err = os.Mkdir(priv, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chtimes(priv, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = assets.RestoreDir(dir, "."); err != nil {
+		t.Fatal(err)
+	}
+	// the directory without stored entry is kept
+	if info, err := os.Stat(priv); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("bad dir mode: %v %v", info.Mode(), err)
+	} else if info.ModTime().Equal(modTime) {
+		t.Errorf("dir mod time not changed by the restored file")
+	}
+}
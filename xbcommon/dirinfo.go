@@ -2,12 +2,21 @@ package xbcommon
 
 import (
 	"os"
+	"path"
 	"time"
 )
 
 type DirInfo struct {
-	path string
-	name string
+	path    string
+	name    string
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// NewDirInfo creates a new directory info. If mode is zero, uses the
+// default `0755` mode.
+func NewDirInfo(pth string, mode os.FileMode, modTime time.Time) *DirInfo {
+	return &DirInfo{path: pth, name: path.Base(pth), mode: mode, modTime: modTime}
 }
 
 func (d DirInfo) Path() string {
@@ -28,12 +37,21 @@ func (DirInfo) Size() int64 {
 	return -1
 }
 
-func (DirInfo) Mode() os.FileMode {
-	return os.ModeDir | 0755
+func (d DirInfo) Mode() os.FileMode {
+	if d.mode == 0 {
+		return os.ModeDir | 0755
+	}
+	return os.ModeDir | d.mode
+}
+
+// stored returns if the directory has a stored entry, with mode and
+// modification time.
+func (d DirInfo) stored() bool {
+	return d.mode != 0
 }
 
-func (DirInfo) ModTime() (t time.Time) {
-	return
+func (d DirInfo) ModTime() time.Time {
+	return d.modTime
 }
 
 func (DirInfo) IsDir() bool {
@@ -0,0 +1,131 @@
+package xbcommon
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Archive formats of Assets.WriteArchive.
+const (
+	ArchiveTar   = "tar"
+	ArchiveTarGz = "tar.gz"
+	ArchiveZip   = "zip"
+)
+
+// ArchiveFormatOf returns the archive format of file name extension. If the
+// extension is unknown, returns blank string.
+func ArchiveFormatOf(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz
+	case strings.HasSuffix(name, ".tar"):
+		return ArchiveTar
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip
+	}
+	return ""
+}
+
+// exportWalk calls cb for each node of assets tree, sorted by name. The
+// directories are visited before its children.
+func (assets *Assets) exportWalk(cb func(name string, n Node) error) error {
+	return assets.Root().Walk(func(dir, name string, n Node, _ interface{}) (interface{}, error) {
+		return nil, cb(path.Join(dir, name), n)
+	})
+}
+
+// WriteTar streams all assets to w as a tar archive, with the mode and
+// modification time of assets. Symlinks are stored as symlink entries.
+func (assets *Assets) WriteTar(w io.Writer) (err error) {
+	tw := tar.NewWriter(w)
+	if err = assets.exportWalk(func(name string, n Node) (err error) {
+		h := &tar.Header{
+			Name:    name,
+			Mode:    int64(n.Mode().Perm()),
+			ModTime: n.ModTime(),
+		}
+		switch {
+		case n.IsDir():
+			h.Typeflag, h.Name = tar.TypeDir, name+"/"
+			return tw.WriteHeader(h)
+		case n.Mode()&os.ModeSymlink != 0:
+			h.Typeflag = tar.TypeSymlink
+			if l, ok := n.(Linker); ok {
+				h.Linkname = l.Link()
+			}
+			return tw.WriteHeader(h)
+		}
+		h.Typeflag, h.Size = tar.TypeReg, n.Size()
+		if err = tw.WriteHeader(h); err != nil {
+			return
+		}
+		return copyAsset(tw, n.(Asset))
+	}); err != nil {
+		return
+	}
+	return tw.Close()
+}
+
+// WriteZip streams all assets to w as a zip archive, with the mode and
+// modification time of assets. Symlinks are stored as symlink entries.
+func (assets *Assets) WriteZip(w io.Writer) (err error) {
+	zw := zip.NewWriter(w)
+	if err = assets.exportWalk(func(name string, n Node) (err error) {
+		h := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: n.ModTime()}
+		h.SetMode(n.Mode())
+		if n.IsDir() {
+			h.Name, h.Method = name+"/", zip.Store
+			_, err = zw.CreateHeader(h)
+			return
+		}
+		var fw io.Writer
+		if fw, err = zw.CreateHeader(h); err != nil {
+			return
+		}
+		if n.Mode()&os.ModeSymlink != 0 {
+			if l, ok := n.(Linker); ok {
+				_, err = io.WriteString(fw, l.Link())
+			}
+			return
+		}
+		return copyAsset(fw, n.(Asset))
+	}); err != nil {
+		return
+	}
+	return zw.Close()
+}
+
+// WriteArchive streams all assets to w as a archive of format: ArchiveTar,
+// ArchiveTarGz or ArchiveZip.
+func (assets *Assets) WriteArchive(w io.Writer, format string) (err error) {
+	switch format {
+	case ArchiveTar:
+		return assets.WriteTar(w)
+	case ArchiveTarGz:
+		gw := gzip.NewWriter(w)
+		if err = assets.WriteTar(gw); err != nil {
+			return
+		}
+		return gw.Close()
+	case ArchiveZip:
+		return assets.WriteZip(w)
+	}
+	return fmt.Errorf("unknown archive format %q", format)
+}
+
+func copyAsset(w io.Writer, asset Asset) (err error) {
+	r, err := asset.Reader()
+	if err != nil {
+		return fmt.Errorf("%s: %v", asset.Path(), err)
+	}
+	defer r.Close()
+	_, err = io.Copy(w, r)
+	return
+}
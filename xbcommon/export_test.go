@@ -0,0 +1,99 @@
+package xbcommon
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	iocommon "github.com/moisespsena-go/io-common"
+)
+
+func exportTestAssets() *Assets {
+	modTime := time.Unix(1577934245, 0)
+	link := NewFileInfo("a/l.txt", 0, 0644, modTime, time.Time{})
+	link.SetLink("b.txt")
+	return NewAssets(
+		NewFile(NewFileInfo("a/b.txt", 1, os.FileMode(0640), modTime, time.Time{}), func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte("b")), nil
+		}, nil),
+		NewFile(NewFileInfo("a", 0, os.ModeDir|0700, modTime, time.Time{}), nil, nil),
+		NewFile(link, nil, nil),
+	)
+}
+
+func TestAssetsWriteTar(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestAssets().WriteTar(&buf); err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(&buf)
+	var got []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, h.Name)
+		switch h.Name {
+		case "a/":
+			if h.Typeflag != tar.TypeDir || h.Mode != 0700 {
+				t.Errorf("bad dir header %+v", h)
+			}
+		case "a/b.txt":
+			data, _ := ioutil.ReadAll(tr)
+			if h.Mode != 0640 || h.ModTime.Unix() != 1577934245 || string(data) != "b" {
+				t.Errorf("bad file header %+v or data %q", h, data)
+			}
+		case "a/l.txt":
+			if h.Typeflag != tar.TypeSymlink || h.Linkname != "b.txt" {
+				t.Errorf("bad link header %+v", h)
+			}
+		}
+	}
+	if len(got) != 3 || got[0] != "a/" {
+		t.Errorf("bad entries %v", got)
+	}
+}
+
+func TestAssetsWriteZip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := exportTestAssets().WriteZip(&buf); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(zr.File) != 3 {
+		t.Fatalf("bad entries count %d", len(zr.File))
+	}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := ioutil.ReadAll(r)
+		r.Close()
+		switch f.Name {
+		case "a/":
+			if !f.Mode().IsDir() || f.Mode().Perm() != 0700 {
+				t.Errorf("bad dir mode %v", f.Mode())
+			}
+		case "a/b.txt":
+			if f.Mode() != 0640 || string(data) != "b" {
+				t.Errorf("bad file mode %v or data %q", f.Mode(), data)
+			}
+		case "a/l.txt":
+			if f.Mode()&os.ModeSymlink == 0 || string(data) != "b.txt" {
+				t.Errorf("bad link mode %v or target %q", f.Mode(), data)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package xbcommon
 
 import (
 	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -20,8 +21,10 @@ import (
 type File struct {
 	*FileInfo
 	nodeCommon
-	reader func() (iocommon.ReadSeekCloser, error)
-	digest *[sha256.Size]byte
+	reader    func() (iocommon.ReadSeekCloser, error)
+	digest    *[sha256.Size]byte
+	digest384 *[sha512.Size384]byte
+	cache     *DataCache
 }
 
 func NewFile(fileInfo *FileInfo, reader func() (iocommon.ReadSeekCloser, error), digest *[sha256.Size]byte) *File {
@@ -45,10 +48,27 @@ func (f *File) ImportLocal(localPath, name string, info os.FileInfo) (err error)
 	return
 }
 
+// Reader returns the reader of file data. If the file has a cache, the
+// reader of cached data.
 func (f *File) Reader() (iocommon.ReadSeekCloser, error) {
+	if f.cache != nil {
+		return f.cache.reader(f)
+	}
 	return f.reader()
 }
 
+// SetCache sets the data cache of file. Must be set before the concurrent
+// use of file.
+func (f *File) SetCache(cache *DataCache) *File {
+	f.cache = cache
+	return f
+}
+
+// Cache returns the data cache of file, if any.
+func (f *File) Cache() *DataCache {
+	return f.cache
+}
+
 func (f *File) Digest() (d [sha256.Size]byte) {
 	if f.digest == nil {
 		return
@@ -56,8 +76,29 @@ func (f *File) Digest() (d [sha256.Size]byte) {
 	return *f.digest
 }
 
-func (f File) Data() ([]byte, error) {
-	if r, err := f.Reader(); err != nil {
+// SetDigest384 sets the SHA-384 digest used by Integrity.
+func (f *File) SetDigest384(digest *[sha512.Size384]byte) *File {
+	f.digest384 = digest
+	return f
+}
+
+// Integrity returns the Subresource Integrity value of file.
+func (f *File) Integrity() string {
+	return Integrity(f.Digest(), f.digest384)
+}
+
+// Data returns the file data. If the file has a cache, the cached data,
+// which must not be modified.
+func (f *File) Data() ([]byte, error) {
+	if f.cache != nil {
+		return f.cache.Data(f)
+	}
+	return f.readData()
+}
+
+// readData reads the file data, without cache.
+func (f *File) readData() ([]byte, error) {
+	if r, err := f.reader(); err != nil {
 		return nil, err
 	} else {
 		defer func() {
@@ -88,6 +129,9 @@ func (f *File) MustDataS() string {
 }
 
 func (f *File) Save(dest string) (err error) {
+	if f.link != "" {
+		return f.saveLink(dest)
+	}
 	var r io.ReadCloser
 	if r, err = f.Reader(); err != nil {
 		return
@@ -19,8 +19,14 @@ type FileInfo struct {
 	mode       os.FileMode
 	modTime    time.Time
 	changeTime time.Time
+	link       string
+	metadata   map[string]string
 }
 
+// fileInfoFlagMetadata is a flag of the marshaled mode, on a bit not used
+// by os.FileMode, marking the metadata presence.
+const fileInfoFlagMetadata uint32 = 1 << 18
+
 func NewFileInfo(pth string, size int64, mode os.FileMode, modTime, changeTime time.Time) *FileInfo {
 	return &FileInfo{path: pth, name: path.Base(pth), size: size, mode: mode, modTime: modTime, changeTime: changeTime}
 }
@@ -45,7 +51,7 @@ func (fi *FileInfo) ChangeTime() time.Time {
 	return fi.changeTime
 }
 func (fi FileInfo) IsDir() bool {
-	return false
+	return fi.mode.IsDir()
 }
 func (fi FileInfo) Sys() interface{} {
 	return nil
@@ -55,7 +61,11 @@ func (fi *FileInfo) Marshal(w io.Writer) (err error) {
 	if err = binary.Write(w, BinaryDir, fi.size); err != nil {
 		return fmt.Errorf("Write Size: %v", err)
 	}
-	if err = binary.Write(w, BinaryDir, uint32(fi.mode)); err != nil {
+	mode := uint32(fi.mode)
+	if len(fi.metadata) > 0 {
+		mode |= fileInfoFlagMetadata
+	}
+	if err = binary.Write(w, BinaryDir, mode); err != nil {
 		return fmt.Errorf("Write Mode: %v", err)
 	}
 	if err = binary.Write(w, BinaryDir, uint64(fi.modTime.UnixNano())); err != nil {
@@ -71,6 +81,19 @@ func (fi *FileInfo) Marshal(w io.Writer) (err error) {
 	if _, err = w.Write([]byte(pth)); err != nil {
 		return fmt.Errorf("Write Path: %v", err)
 	}
+	if fi.mode&os.ModeSymlink != 0 {
+		if err = binary.Write(w, BinaryDir, uint32(len(fi.link))); err != nil {
+			return fmt.Errorf("Write Link Size: %v", err)
+		}
+		if _, err = w.Write([]byte(fi.link)); err != nil {
+			return fmt.Errorf("Write Link: %v", err)
+		}
+	}
+	if len(fi.metadata) > 0 {
+		if err = marshalMetadata(w, fi.metadata); err != nil {
+			return fmt.Errorf("Write Metadata: %v", err)
+		}
+	}
 	return
 }
 
@@ -85,7 +108,8 @@ func (fi *FileInfo) Unmarshal(r io.Reader) (err error) {
 	if err = binary.Read(r, BinaryDir, &i); err != nil {
 		return fmt.Errorf("Read Mode: %v", err)
 	}
-	fi.mode = os.FileMode(i)
+	hasMetadata := i&fileInfoFlagMetadata != 0
+	fi.mode = os.FileMode(i &^ fileInfoFlagMetadata)
 
 	if err = binary.Read(r, BinaryDir, &i64); err != nil {
 		return fmt.Errorf("Read ModTime: %v", err)
@@ -109,5 +133,20 @@ func (fi *FileInfo) Unmarshal(r io.Reader) (err error) {
 	}
 	fi.path = string(b)
 	fi.name = path.Base(fi.path)
+	if fi.mode&os.ModeSymlink != 0 {
+		if err = binary.Read(r, BinaryDir, &i); err != nil {
+			return fmt.Errorf("Read Link Size: %v", err)
+		}
+		b = make([]byte, i)
+		if _, err = io.ReadFull(r, b); err != nil {
+			return fmt.Errorf("Read Link: %v", err)
+		}
+		fi.link = string(b)
+	}
+	if hasMetadata {
+		if fi.metadata, err = unmarshalMetadata(r); err != nil {
+			return fmt.Errorf("Read Metadata: %v", err)
+		}
+	}
 	return
 }
@@ -0,0 +1,95 @@
+package xbcommon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"strings"
+)
+
+// DefaultHashedNameLength is the default count of hex digest chars used by
+// hashed names.
+const DefaultHashedNameLength = 8
+
+// NameResolver resolves original asset names to hashed (fingerprinted) names
+// and vice versa.
+type NameResolver interface {
+	HashedName(name string) string
+	Original(hashed string) string
+}
+
+// HashedName returns the fingerprinted name `name.<shorthash>.ext` of asset.
+func HashedName(name string, digest [sha256.Size]byte, length int) string {
+	if length <= 0 {
+		length = DefaultHashedNameLength
+	}
+	if max := sha256.Size * 2; length > max {
+		length = max
+	}
+
+	var (
+		hash = hex.EncodeToString(digest[:])[0:length]
+		dir  = path.Dir(name)
+		base = path.Base(name)
+		ext  string
+	)
+
+	// ignore hidden files dot
+	if pos := strings.LastIndexByte(base, '.'); pos > 0 {
+		base, ext = base[0:pos], base[pos:]
+	}
+
+	base += "." + hash + ext
+	if dir == "." {
+		return base
+	}
+	return dir + "/" + base
+}
+
+// SetHashedNames sets the original to hashed names map. If only is false,
+// each asset is also published under the hashed name.
+func (assets *Assets) SetHashedNames(names map[string]string, only bool) {
+	assets.mu.Lock()
+	defer assets.mu.Unlock()
+
+	assets.hashedNames = names
+	assets.originalNames = make(map[string]string, len(names))
+	assets.hashedOnly = only
+	for original, hashed := range names {
+		assets.originalNames[hashed] = original
+	}
+	if assets.Assets != nil {
+		assets.applyHashedNames()
+	}
+}
+
+func (assets *Assets) applyHashedNames() {
+	if assets.hashedOnly || len(assets.hashedNames) == 0 {
+		return
+	}
+	for original, hashed := range assets.hashedNames {
+		if asset, ok := (*assets.Assets)[original]; ok {
+			if _, ok = (*assets.Assets)[hashed]; !ok {
+				(*assets.Assets)[hashed] = NewAlias(hashed, asset)
+			}
+		}
+	}
+}
+
+// HashedName returns the hashed name of asset. If does not have a hashed
+// name, returns name.
+func (assets *Assets) HashedName(name string) string {
+	if hashed, ok := assets.hashedNames[name]; ok {
+		return hashed
+	}
+	return name
+}
+
+// Original returns the original name of the hashed asset name. If hashed
+// is not a hashed name, returns hashed.
+func (assets *Assets) Original(hashed string) string {
+	if original, ok := assets.originalNames[hashed]; ok {
+		return original
+	}
+	return hashed
+}
@@ -0,0 +1,60 @@
+package xbcommon
+
+import (
+	"crypto/sha256"
+	"os"
+	"testing"
+	"time"
+
+	iocommon "github.com/moisespsena-go/io-common"
+)
+
+func TestHashedName(t *testing.T) {
+	var digest [sha256.Size]byte
+	for i := range digest {
+		digest[i] = byte(i)
+	}
+	for _, tt := range []struct {
+		name   string
+		length int
+		out    string
+	}{
+		{"app.css", 0, "app.00010203.css"},
+		{"static/css/app.min.css", 4, "static/css/app.min.0001.css"},
+		{"LICENSE", 6, "LICENSE.000102"},
+		{"dir/.hidden", 2, "dir/.hidden.00"},
+	} {
+		if out := HashedName(tt.name, digest, tt.length); out != tt.out {
+			t.Errorf("HashedName(%q, %d):\nhave %q\nwant %q", tt.name, tt.length, out, tt.out)
+		}
+	}
+}
+
+func TestAssetsHashedNames(t *testing.T) {
+	newAssets := func() *Assets {
+		return NewAssets(NewFile(NewFileInfo("a/b.txt", 1, os.FileMode(0644), time.Time{}, time.Time{}), func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte("b")), nil
+		}, nil))
+	}
+	names := map[string]string{"a/b.txt": "a/b.01020304.txt"}
+
+	assets := newAssets()
+	assets.SetHashedNames(names, false)
+	if a, ok := assets.Get("a/b.01020304.txt"); !ok {
+		t.Fatal("hashed asset not found")
+	} else if a.Path() != "a/b.01020304.txt" || a.Name() != "b.01020304.txt" {
+		t.Errorf("bad alias path %q or name %q", a.Path(), a.Name())
+	}
+	if _, err := assets.Root().Get("a/b.01020304.txt"); err != nil {
+		t.Errorf("hashed asset not found in tree: %v", err)
+	}
+
+	assets = newAssets()
+	assets.SetHashedNames(names, true)
+	if _, err := assets.Root().Get("a/b.01020304.txt"); err == nil {
+		t.Error("hashed only mode publishes hashed alias")
+	}
+	if assets.Original("a/b.01020304.txt") != "a/b.txt" || assets.HashedName("a/b.txt") != "a/b.01020304.txt" {
+		t.Error("bad names resolution")
+	}
+}
@@ -0,0 +1,65 @@
+package xbcommon
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Integrity returns the Subresource Integrity value of the digests:
+// `sha256-<base64>` and, if digest384 is not nil, ` sha384-<base64>`.
+func Integrity(digest [sha256.Size]byte, digest384 *[sha512.Size384]byte) string {
+	s := "sha256-" + base64.StdEncoding.EncodeToString(digest[:])
+	if digest384 != nil {
+		s += " sha384-" + base64.StdEncoding.EncodeToString(digest384[:])
+	}
+	return s
+}
+
+// IntegritySHA384 reads the asset data and returns the `sha384-<base64>`
+// Subresource Integrity value.
+func IntegritySHA384(asset Asset) (s string, err error) {
+	r, err := asset.Reader()
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	h := sha512.New384()
+	if _, err = io.Copy(h, r); err != nil {
+		return
+	}
+	return "sha384-" + base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// WriteSHA256Sums writes the digests in the `sha256sum` format, sorted by
+// name. The output is accepted by `sha256sum -c`.
+func WriteSHA256Sums(w io.Writer, digests map[string][sha256.Size]byte) (err error) {
+	names := make([]string, 0, len(digests))
+	for name := range digests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		d := digests[name]
+		if _, err = fmt.Fprintf(w, "%s  %s\n", hex.EncodeToString(d[:]), name); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WriteSHA256Sums writes the digests of all assets in the `sha256sum` format.
+// Run `sha256sum -c` into the restored directory to verify it.
+func (assets *Assets) WriteSHA256Sums(w io.Writer) (err error) {
+	var digests map[string][sha256.Size]byte
+	if digests, err = assets.Digests(); err != nil {
+		return
+	}
+	return WriteSHA256Sums(w, digests)
+}
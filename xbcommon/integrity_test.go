@@ -0,0 +1,33 @@
+package xbcommon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"testing"
+)
+
+func TestIntegrity(t *testing.T) {
+	var (
+		d    = sha256.Sum256(nil)
+		d384 = sha512.Sum384(nil)
+	)
+	if s := Integrity(d, nil); s != "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=" {
+		t.Errorf("bad sha256 integrity %q", s)
+	}
+	if s := Integrity(d, &d384); s != "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU= sha384-OLBgp1GsljhM2TJ+sbHjaiH9txEUvgdDTAzHv2P24donTt6/529l+9Ua0vFImLlb" {
+		t.Errorf("bad sha384 integrity %q", s)
+	}
+}
+
+func TestWriteSHA256Sums(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSHA256Sums(&buf, map[string][sha256.Size]byte{"b": sha256.Sum256(nil), "a/c": sha256.Sum256([]byte("c"))}); err != nil {
+		t.Fatal(err)
+	}
+	want := "2e7d2c03a9507ae265ecf5b5356885a53393a2029d241394997265a1a25aefc6  a/c\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  b\n"
+	if buf.String() != want {
+		t.Errorf("have %q\nwant %q", buf.String(), want)
+	}
+}
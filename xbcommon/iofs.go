@@ -0,0 +1,154 @@
+//go:build go1.16
+// +build go1.16
+
+package xbcommon
+
+import (
+	"io"
+	"io/fs"
+	"path"
+
+	oscommon "github.com/moisespsena-go/os-common"
+)
+
+// IOFS is the io/fs file system of the nodes tree, created by NewIOFS. The
+// symlinks are listed as symlinks, followed by Open and read by ReadLink.
+type IOFS struct {
+	root NodeDir
+}
+
+// NewIOFS returns the io/fs file system of root, usable by the io/fs
+// functions and by testing/fstest.TestFS.
+func NewIOFS(root NodeDir) *IOFS {
+	return &IOFS{root}
+}
+
+// Open opens the named node, following the symlinks.
+func (f *IOFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	node, err := ResolveLink(f.root, name)
+	if err != nil {
+		return nil, ioFSError("open", name, err)
+	}
+	switch n := node.(type) {
+	case NodeDir:
+		return &ioFSDir{dir: n, name: name}, nil
+	case Asset:
+		r, err := n.Reader()
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &ioFSFile{r, n}, nil
+	default:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+}
+
+// Lstat returns the named node info, without following the symlink.
+func (f *IOFS) Lstat(name string) (fs.FileInfo, error) {
+	return f.lstat("lstat", name)
+}
+
+// ReadLink returns the symlink target.
+func (f *IOFS) ReadLink(name string) (string, error) {
+	node, err := f.lstat("readlink", name)
+	if err != nil {
+		return "", err
+	}
+	if l, ok := node.(Linker); ok && l.Link() != "" {
+		return l.Link(), nil
+	}
+	return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+}
+
+func (f *IOFS) lstat(op, name string) (Node, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return f.root, nil
+	}
+	var node Node = f.root
+	if dir := path.Dir(name); dir != "." {
+		var err error
+		if node, err = ResolveLink(f.root, dir); err != nil {
+			return nil, ioFSError(op, name, err)
+		}
+	}
+	if dir, ok := node.(NodeDir); ok {
+		if node, ok = dir.GetChild(path.Base(name)); ok {
+			return node, nil
+		}
+	}
+	return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// ioFSError returns the *fs.PathError of err, with fs.ErrNotExist for the
+// not found nodes.
+func ioFSError(op, name string, err error) error {
+	if oscommon.IsNotFound(err) || oscommon.IsNotDir(err) {
+		err = fs.ErrNotExist
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
+
+// ioFSFile is the opened asset. The asset reader may not be seekable, so
+// only the reads are exposed.
+type ioFSFile struct {
+	r     io.ReadCloser
+	asset Asset
+}
+
+func (f *ioFSFile) Stat() (fs.FileInfo, error) {
+	return f.asset, nil
+}
+
+func (f *ioFSFile) Read(p []byte) (int, error) {
+	return f.r.Read(p)
+}
+
+func (f *ioFSFile) Close() error {
+	return f.r.Close()
+}
+
+// ioFSDir is the opened directory.
+type ioFSDir struct {
+	dir    NodeDir
+	name   string
+	offset int
+}
+
+func (d *ioFSDir) Stat() (fs.FileInfo, error) {
+	return d.dir, nil
+}
+
+func (d *ioFSDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid}
+}
+
+func (d *ioFSDir) Close() error {
+	return nil
+}
+
+func (d *ioFSDir) ReadDir(count int) (entries []fs.DirEntry, err error) {
+	var (
+		list = d.dir.List()
+		n    = len(list) - d.offset
+	)
+	if count > 0 {
+		if n == 0 {
+			return nil, io.EOF
+		}
+		if n > count {
+			n = count
+		}
+	}
+	entries = make([]fs.DirEntry, n)
+	for i := range entries {
+		entries[i] = fs.FileInfoToDirEntry(list[d.offset+i])
+	}
+	d.offset += n
+	return
+}
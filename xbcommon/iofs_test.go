@@ -0,0 +1,46 @@
+//go:build go1.16
+// +build go1.16
+
+package xbcommon
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	iocommon "github.com/moisespsena-go/io-common"
+)
+
+func TestIOFS(t *testing.T) {
+	newFile := func(name, link, data string) Asset {
+		info := NewFileInfo(name, int64(len(data)), os.FileMode(0644), time.Time{}, time.Time{})
+		if link != "" {
+			info.SetLink(link)
+		}
+		return NewFile(info, func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte(data)), nil
+		}, nil)
+	}
+	fsys := NewIOFS(NewAssets(
+		newFile("a/b.txt", "", "b"),
+		newFile("a/c/d.txt", "", "d"),
+		newFile("a/up", "../e.txt", ""),
+		newFile("e.txt", "", "e"),
+		newFile("l", "a", ""),
+	).Root())
+
+	if err := fstest.TestFS(fsys, "a/b.txt", "a/c/d.txt", "a/up", "e.txt", "l"); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, data := range map[string]string{"a/up": "e", "l/b.txt": "b"} {
+		if b, err := fs.ReadFile(fsys, name); err != nil || string(b) != data {
+			t.Errorf("ReadFile(%q): have %q, %v; want %q", name, b, err, data)
+		}
+	}
+	if _, err := fsys.Open("x"); !os.IsNotExist(err) {
+		t.Errorf("Open(x): have %v, want not exist error", err)
+	}
+}
@@ -0,0 +1,119 @@
+package xbcommon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	oscommon "github.com/moisespsena-go/os-common"
+	path_helpers "github.com/moisespsena-go/path-helpers"
+)
+
+// maxLinkHops is the max number of symlinks followed by ResolveLink.
+const maxLinkHops = 40
+
+var (
+	ErrLinkEscapes = errors.New("target escapes the assets root")
+	ErrLinkLoop    = errors.New("too many levels of symbolic links")
+)
+
+// LinkError records an error on symlink resolution.
+type LinkError struct {
+	Name string
+	Link string
+	Err  error
+}
+
+func (e *LinkError) Error() string {
+	return fmt.Sprintf("symlink %q -> %q: %v", e.Name, e.Link, e.Err)
+}
+
+// Linker is implemented by the symlink nodes.
+type Linker interface {
+	Link() string
+}
+
+// SetLink marks the file info as a symlink to target, relative to the file
+// directory.
+func (fi *FileInfo) SetLink(target string) *FileInfo {
+	fi.link = target
+	fi.mode |= os.ModeSymlink
+	fi.size = 0
+	return fi
+}
+
+// Link returns the symlink target. Returns a empty string if it is not a
+// symlink.
+func (fi *FileInfo) Link() string {
+	return fi.link
+}
+
+// LinkTarget returns the target name of the symlink name. Returns a
+// *LinkError if the target is absolute or escapes the assets root.
+func LinkTarget(name, link string) (target string, err error) {
+	if path.IsAbs(link) {
+		return "", &LinkError{name, link, ErrLinkEscapes}
+	}
+	target = path.Join(path.Dir(name), link)
+	if target == ".." || strings.HasPrefix(target, "../") {
+		return "", &LinkError{name, link, ErrLinkEscapes}
+	}
+	return
+}
+
+// ResolveLink gets the node by name, following the symlinks of each name
+// component, like os.Stat.
+func ResolveLink(root NodeDir, name string) (node Node, err error) {
+	var (
+		parts = strings.Split(path.Clean(name), "/")
+		hops  int
+		ok    bool
+	)
+	node = root
+	for i := 0; i < len(parts); i++ {
+		if parts[i] == "." {
+			continue
+		}
+		dir, isDir := node.(NodeDir)
+		if !isDir {
+			return nil, oscommon.ErrNotDir(path.Join(parts[0:i]...))
+		}
+		if node, ok = dir.GetChild(parts[i]); !ok {
+			return nil, oscommon.ErrNotFound(name)
+		}
+		if l, isLink := node.(Linker); isLink && l.Link() != "" {
+			linkName := path.Join(parts[0 : i+1]...)
+			if hops++; hops > maxLinkHops {
+				return nil, &LinkError{linkName, l.Link(), ErrLinkLoop}
+			}
+			var target string
+			if target, err = LinkTarget(linkName, l.Link()); err != nil {
+				return nil, err
+			}
+			parts = append(strings.Split(target, "/"), parts[i+1:]...)
+			node, i = root, -1
+		}
+	}
+	return
+}
+
+// saveLink creates the symlink at dest, replacing existing file.
+func (f *File) saveLink(dest string) (err error) {
+	if _, err = LinkTarget(f.path, f.link); err != nil {
+		return
+	}
+	if err = path_helpers.MkdirAllIfNotExists(filepath.Dir(dest)); err != nil {
+		return
+	}
+	if _, err = os.Lstat(dest); err == nil {
+		if err = os.Remove(dest); err != nil {
+			return
+		}
+	} else if !os.IsNotExist(err) {
+		return
+	}
+	return os.Symlink(filepath.FromSlash(f.link), dest)
+}
@@ -0,0 +1,88 @@
+package xbcommon
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	iocommon "github.com/moisespsena-go/io-common"
+)
+
+func TestLinkTarget(t *testing.T) {
+	for _, tt := range []struct {
+		name, link, target string
+		escapes            bool
+	}{
+		{"a/b", "c", "a/c", false},
+		{"a/b", "../c", "c", false},
+		{"a/b", "../../c", "", true},
+		{"a/b", "/etc/passwd", "", true},
+		{"b", "..", "", true},
+	} {
+		target, err := LinkTarget(tt.name, tt.link)
+		if tt.escapes {
+			if err == nil {
+				t.Errorf("LinkTarget(%q, %q): escape not detected", tt.name, tt.link)
+			}
+		} else if err != nil || target != tt.target {
+			t.Errorf("LinkTarget(%q, %q): have %q, %v; want %q", tt.name, tt.link, target, err, tt.target)
+		}
+	}
+}
+
+func TestResolveLink(t *testing.T) {
+	newFile := func(name, link, data string) Asset {
+		info := NewFileInfo(name, int64(len(data)), os.FileMode(0644), time.Time{}, time.Time{})
+		if link != "" {
+			info.SetLink(link)
+		}
+		return NewFile(info, func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte(data)), nil
+		}, nil)
+	}
+	root := NewAssets(
+		newFile("a/b.txt", "", "b"),
+		newFile("a/up", "../c.txt", ""),
+		newFile("c.txt", "", "c"),
+		newFile("d", "a", ""),
+		newFile("loop", "loop", ""),
+		newFile("esc", "../x", ""),
+	).Root()
+
+	for name, data := range map[string]string{"d/b.txt": "b", "d/up": "c", "a/up": "c"} {
+		n, err := ResolveLink(root, name)
+		if err != nil {
+			t.Errorf("ResolveLink(%q): %v", name, err)
+			continue
+		}
+		if b, _ := n.(Asset).Data(); string(b) != data {
+			t.Errorf("ResolveLink(%q): have %q, want %q", name, b, data)
+		}
+	}
+	if n, err := ResolveLink(root, "d"); err != nil || !n.IsDir() {
+		t.Errorf("ResolveLink(\"d\"): dir expected, have %v, %v", n, err)
+	}
+	for _, name := range []string{"loop", "esc"} {
+		if _, err := ResolveLink(root, name); err == nil {
+			t.Errorf("ResolveLink(%q): error expected", name)
+		} else if _, ok := err.(*LinkError); !ok {
+			t.Errorf("ResolveLink(%q): *LinkError expected, have %T", name, err)
+		}
+	}
+}
+
+func TestFileInfoMarshalLink(t *testing.T) {
+	var buf bytes.Buffer
+	info := NewFileInfo("a/b", 10, os.FileMode(0644), time.Unix(1, 0), time.Unix(2, 0)).SetLink("../c")
+	if err := info.Marshal(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var info2 FileInfo
+	if err := info2.Unmarshal(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if info2.Link() != "../c" || info2.Mode()&os.ModeSymlink == 0 || info2.Size() != 0 || info2.Path() != "a/b" {
+		t.Errorf("bad unmarshaled link info: %+v", info2)
+	}
+}
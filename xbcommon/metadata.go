@@ -0,0 +1,112 @@
+package xbcommon
+
+import (
+	"encoding/binary"
+	"io"
+	"mime"
+	"path"
+	"sort"
+)
+
+const (
+	// MetadataContentType is the metadata key of asset content type.
+	MetadataContentType = "content-type"
+	// MetadataHeaderPrefix is the prefix of metadata keys sent as HTTP
+	// response headers, example: `header.Cache-Control`.
+	MetadataHeaderPrefix = "header."
+)
+
+// SetMetadata sets the metadata map and returns the file info.
+func (fi *FileInfo) SetMetadata(metadata map[string]string) *FileInfo {
+	fi.metadata = metadata
+	return fi
+}
+
+// Metadata returns the metadata map. Do not modify it.
+func (fi *FileInfo) Metadata() map[string]string {
+	return fi.metadata
+}
+
+// ContentType returns the `content-type` metadata value.
+func (fi *FileInfo) ContentType() string {
+	return fi.metadata[MetadataContentType]
+}
+
+// Metadata returns the metadata of the bindata asset, if exists.
+func (f LocalFile) Metadata() map[string]string {
+	if f.asset != nil {
+		return f.asset.Metadata()
+	}
+	return nil
+}
+
+// ContentType returns the content type of the bindata asset, or detected
+// by file extension.
+func (f LocalFile) ContentType() string {
+	if f.asset != nil {
+		if ct := f.asset.ContentType(); ct != "" {
+			return ct
+		}
+	}
+	return mime.TypeByExtension(path.Ext(f.Name()))
+}
+
+func marshalMetadata(w io.Writer, metadata map[string]string) (err error) {
+	var keys = make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err = binary.Write(w, BinaryDir, uint32(len(keys))); err != nil {
+		return
+	}
+	for _, key := range keys {
+		if err = marshalString(w, key); err != nil {
+			return
+		}
+		if err = marshalString(w, metadata[key]); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func unmarshalMetadata(r io.Reader) (metadata map[string]string, err error) {
+	var count uint32
+	if err = binary.Read(r, BinaryDir, &count); err != nil {
+		return
+	}
+	metadata = make(map[string]string, count)
+	for i := uint32(0); i < count; i++ {
+		var key, value string
+		if key, err = unmarshalString(r); err != nil {
+			return
+		}
+		if value, err = unmarshalString(r); err != nil {
+			return
+		}
+		metadata[key] = value
+	}
+	return
+}
+
+func marshalString(w io.Writer, s string) (err error) {
+	if err = binary.Write(w, BinaryDir, uint32(len(s))); err != nil {
+		return
+	}
+	_, err = io.WriteString(w, s)
+	return
+}
+
+func unmarshalString(r io.Reader) (s string, err error) {
+	var size uint32
+	if err = binary.Read(r, BinaryDir, &size); err != nil {
+		return
+	}
+	b := make([]byte, size)
+	if _, err = io.ReadFull(r, b); err != nil {
+		return
+	}
+	return string(b), nil
+}
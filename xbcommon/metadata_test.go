@@ -0,0 +1,35 @@
+package xbcommon
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileInfoMarshalMetadata(t *testing.T) {
+	metadata := map[string]string{MetadataContentType: "text/css", "header.Cache-Control": "max-age=60"}
+	for _, info := range []*FileInfo{
+		NewFileInfo("a.css", 10, os.FileMode(0644), time.Unix(1, 0), time.Unix(2, 0)).SetMetadata(metadata),
+		NewFileInfo("b.css", 0, os.FileMode(0644), time.Unix(1, 0), time.Unix(2, 0)).SetLink("a.css").SetMetadata(metadata),
+	} {
+		var buf bytes.Buffer
+		if err := info.Marshal(&buf); err != nil {
+			t.Fatal(err)
+		}
+		var info2 FileInfo
+		if err := info2.Unmarshal(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(info2.Metadata(), metadata) {
+			t.Errorf("%s: bad metadata %v", info.Path(), info2.Metadata())
+		}
+		if info2.Mode() != info.Mode() || info2.Link() != info.Link() {
+			t.Errorf("%s: bad mode %v or link %q", info.Path(), info2.Mode(), info2.Link())
+		}
+		if info2.ContentType() != "text/css" {
+			t.Errorf("%s: bad content type %q", info.Path(), info2.ContentType())
+		}
+	}
+}
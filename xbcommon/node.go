@@ -37,6 +37,12 @@ type Asset interface {
 	Node
 	Reader() (iocommon.ReadSeekCloser, error)
 	Digest() [sha256.Size]byte
+	// Integrity returns the Subresource Integrity value.
+	Integrity() string
+	// Metadata returns the asset metadata.
+	Metadata() map[string]string
+	// ContentType returns the `content-type` metadata value.
+	ContentType() string
 	Data() ([]byte, error)
 	DataS() (string, error)
 	MustData() []byte
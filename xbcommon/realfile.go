@@ -2,8 +2,10 @@ package xbcommon
 
 import (
 	"crypto/sha256"
+	"os"
 
 	"github.com/moisespsena-go/assetfs"
+	"github.com/moisespsena-go/assetfs/assetfsapi"
 	"github.com/moisespsena-go/assetfs/local"
 )
 
@@ -30,3 +32,21 @@ func (f LocalFile) Digest() [sha256.Size]byte {
 	}
 	return *f.digest
 }
+
+func (f LocalFile) Integrity() string {
+	return Integrity(f.Digest(), nil)
+}
+
+// NewLocalTree returns the tree of local files, used by the dev mode file
+// systems. The files maps the local file path to the asset name.
+func NewLocalTree(files map[string]string) (*Tree, error) {
+	var assets = make([]Asset, 0, len(files))
+	for pth, name := range files {
+		info, err := os.Stat(pth)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, &LocalFile{RealFileInfo: assetfs.NewRealFileInfo(assetfsapi.OsFileInfoToBasic(name, info), pth)})
+	}
+	return NewTree(assets...), nil
+}
@@ -1,6 +1,7 @@
 package xbcommon
 
 import (
+	"os"
 	"path"
 )
 
@@ -9,6 +10,7 @@ type assetTree struct {
 	Path     string
 	children map[string]*assetTree
 	level    int
+	dirInfo  os.FileInfo
 }
 
 func newAssetTree() *assetTree {
@@ -35,6 +37,16 @@ func (root *assetTree) Add(route []string, asset Asset) {
 	root.Asset = asset
 }
 
+// AddDir adds the directory entry. The directory is created if does not
+// have children.
+func (root *assetTree) AddDir(route []string, info os.FileInfo) {
+	for _, name := range route {
+		root = root.child(name)
+	}
+	root.level = len(route)
+	root.dirInfo = info
+}
+
 func (root *assetTree) Children() (children map[string]Node) {
 	children = map[string]Node{}
 	for name, child := range root.children {
@@ -47,5 +59,9 @@ func (root *assetTree) Node() Node {
 	if root.Asset != nil {
 		return root.Asset
 	}
-	return NewDir(root.level, root.Path, root.Children())
+	d := NewDir(root.level, root.Path, root.Children())
+	if root.dirInfo != nil {
+		d.DirInfo = NewDirInfo(root.Path, root.dirInfo.Mode().Perm(), root.dirInfo.ModTime())
+	}
+	return d
 }
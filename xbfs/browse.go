@@ -0,0 +1,242 @@
+package xbfs
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+const (
+	// BrowseMetaPath is the path prefix of the JSON metadata endpoint of
+	// BrowseHandler.
+	BrowseMetaPath = "/_xb/meta/"
+	// BrowseEventsPath is the path of the change events endpoint of
+	// BrowseHandler.
+	BrowseEventsPath = "/_xb/events"
+)
+
+// BrowseHandler serves the assets of file system for the checking of what
+// ships:
+//
+//	GET /ASSET              the asset, with the FileSystem.ServeHTTP headers
+//	GET /DIR/               the directory listing, JSON if requested by the
+//	                        `Accept: application/json` header
+//	GET /_xb/meta/NAME      the JSON AssetMeta of asset or directory
+//	GET /_xb/events         the change events, see FileSystem.EventsHandler
+type BrowseHandler struct {
+	FS     *FileSystem
+	events http.Handler
+}
+
+// NewBrowseHandler returns the BrowseHandler of fs.
+func NewBrowseHandler(fs *FileSystem) *BrowseHandler {
+	return &BrowseHandler{FS: fs, events: fs.EventsHandler()}
+}
+
+// AssetMeta is the metadata of asset or directory, served by BrowseHandler.
+type AssetMeta struct {
+	Name    string    `json:"name"`
+	Dir     bool      `json:"dir,omitempty"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"`
+	ModTime time.Time `json:"mod_time"`
+	// Link is the symlink target.
+	Link string `json:"link,omitempty"`
+	// HashedName is the hashed or original name of asset, if any.
+	HashedName  string            `json:"hashed_name,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+	Digest      string            `json:"sha256,omitempty"`
+	Integrity   string            `json:"integrity,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// Headers are the FileSystem.ServeHTTP response headers of asset
+	// metadata. See MetadataHandler.
+	Headers http.Header `json:"headers,omitempty"`
+	// Children are the directory entries names.
+	Children []string `json:"children,omitempty"`
+}
+
+func (h *BrowseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch pth := r.URL.Path; {
+	case pth == BrowseEventsPath:
+		h.events.ServeHTTP(w, r)
+	case strings.HasPrefix(pth, BrowseMetaPath):
+		h.serveMeta(w, r, strings.TrimPrefix(pth, BrowseMetaPath))
+	default:
+		name := strings.Trim(path.Clean(pth), "/")
+		if node, err := h.FS.find(h.full(name)); err == nil {
+			if dir, ok := node.(xbcommon.NodeDir); ok {
+				if !strings.HasSuffix(pth, "/") {
+					http.Redirect(w, r, path.Base(pth)+"/", http.StatusMovedPermanently)
+					return
+				}
+				h.serveDir(w, r, name, dir)
+				return
+			}
+		}
+		if h.FS.path != "" {
+			// the FileSystem.ServeHTTP path includes the name space path
+			r2, u := *r, *r.URL
+			u.Path = "/" + h.full(name)
+			r2.URL = &u
+			r = &r2
+		}
+		h.FS.ServeHTTP(w, r)
+	}
+}
+
+// full returns the name relative to the root file system.
+func (h *BrowseHandler) full(name string) string {
+	if h.FS.path != "" {
+		return path.Join(h.FS.path, name)
+	}
+	return name
+}
+
+func (h *BrowseHandler) serveMeta(w http.ResponseWriter, r *http.Request, name string) {
+	name = strings.Trim(path.Clean("/"+name), "/")
+	meta, err := h.Meta(name)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if !meta.Dir {
+		meta.Headers = http.Header{}
+		h.FS.metadataHeaders(r.Context(), "/"+h.full(name), meta.Headers)
+	}
+	writeJSON(w, meta)
+}
+
+// Meta returns the metadata of named asset or directory. The symlinks are
+// followed, reporting the symlink target.
+func (h *BrowseHandler) Meta(name string) (meta *AssetMeta, err error) {
+	var node, link xbcommon.Node
+	if link, err = h.lstat(name); err != nil {
+		return
+	}
+	if node, err = h.FS.find(h.full(name)); err != nil {
+		return
+	}
+
+	meta = &AssetMeta{
+		Name:    name,
+		Dir:     node.IsDir(),
+		Size:    node.Size(),
+		Mode:    node.Mode().String(),
+		ModTime: node.ModTime(),
+	}
+	if l, ok := link.(xbcommon.Linker); ok {
+		meta.Link = l.Link()
+	}
+
+	switch n := node.(type) {
+	case xbcommon.NodeDir:
+		meta.Children = []string{}
+		for _, child := range n.List() {
+			meta.Children = append(meta.Children, child.Name())
+		}
+	case xbcommon.Asset:
+		digest := n.Digest()
+		meta.Digest = hex.EncodeToString(digest[:])
+		meta.Integrity = n.Integrity()
+		meta.ContentType = n.ContentType()
+		meta.Metadata = n.Metadata()
+		if r := h.FS.nameResolver(); r != nil {
+			full := h.full(name)
+			if alt := r.HashedName(full); alt != full {
+				meta.HashedName = alt
+			} else if alt = r.Original(full); alt != full {
+				meta.HashedName = alt
+			}
+		}
+	}
+	return
+}
+
+// lstat returns the named node, without following the symlink.
+func (h *BrowseHandler) lstat(name string) (node xbcommon.Node, err error) {
+	var root = h.FS.tree()
+	if name = h.full(name); name == "" {
+		return root, nil
+	}
+	if node, err = xbcommon.ResolveLink(root, path.Dir(name)); err != nil {
+		return
+	}
+	if dir, ok := node.(xbcommon.NodeDir); ok {
+		if node, ok = dir.GetChild(path.Base(name)); ok {
+			return
+		}
+	}
+	// the hashed or original name
+	return h.FS.find(name)
+}
+
+func (h *BrowseHandler) serveDir(w http.ResponseWriter, r *http.Request, name string, dir xbcommon.NodeDir) {
+	var entries = []*AssetMeta{}
+	for _, child := range dir.List() {
+		meta, err := h.Meta(path.Join(name, child.Name()))
+		if err != nil {
+			// the broken symlink
+			meta = &AssetMeta{Name: path.Join(name, child.Name()), Mode: child.Mode().String(), ModTime: child.ModTime()}
+			if l, ok := child.(xbcommon.Linker); ok {
+				meta.Link = l.Link()
+			}
+		}
+		entries = append(entries, meta)
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		writeJSON(w, entries)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := browseTemplate.Execute(w, map[string]interface{}{
+		"Dir":      "/" + name,
+		"MetaPath": BrowseMetaPath,
+		"Entries":  entries,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+var browseTemplate = template.Must(template.New("browse").Funcs(template.FuncMap{
+	"base":  path.Base,
+	"bytes": func(size int64) string { return humanize.IBytes(uint64(size)) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Dir}}</title></head>
+<body>
+<h1>{{.Dir}}</h1>
+<table>
+<tr><th>Name</th><th>Size</th><th>Mode</th><th>Modified</th><th>Content Type</th><th></th></tr>
+{{if ne .Dir "/"}}<tr><td><a href="../">../</a></td></tr>{{end}}
+{{range .Entries}}<tr>
+<td>{{if .Dir}}<a href="{{base .Name}}/">{{base .Name}}/</a>{{else}}<a href="{{base .Name}}">{{base .Name}}</a>{{end}}{{if .Link}} -&gt; {{.Link}}{{end}}</td>
+<td>{{if not .Dir}}{{bytes .Size}}{{end}}</td>
+<td>{{.Mode}}</td>
+<td>{{.ModTime.Format "2006-01-02 15:04:05"}}</td>
+<td>{{.ContentType}}</td>
+<td><a href="{{$.MetaPath}}{{.Name}}">meta</a></td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
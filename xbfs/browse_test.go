@@ -0,0 +1,72 @@
+package xbfs
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	iocommon "github.com/moisespsena-go/io-common"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+func TestBrowseHandler(t *testing.T) {
+	newFile := func(name, link, data string, metadata map[string]string) xbcommon.Asset {
+		info := xbcommon.NewFileInfo(name, int64(len(data)), os.FileMode(0644), time.Time{}, time.Time{})
+		if link != "" {
+			info.SetLink(link)
+		}
+		info.SetMetadata(metadata)
+		return xbcommon.NewFile(info, func() (iocommon.ReadSeekCloser, error) {
+			return iocommon.NewBytesReadCloser([]byte(data)), nil
+		}, nil)
+	}
+	fs := NewFileSystem(xbcommon.NewAssets(
+		newFile("a/b.txt", "", "b", map[string]string{"header.Cache-Control": "max-age=60"}),
+		newFile("l.txt", "a/b.txt", "", nil),
+	).Root())
+	srv := httptest.NewServer(NewBrowseHandler(fs))
+	defer srv.Close()
+
+	get := func(pth string, header ...string) (res *http.Response, body string) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+pth, nil)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		res, err := srv.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		data, _ := ioutil.ReadAll(res.Body)
+		return res, string(data)
+	}
+
+	if res, body := get("/a/b.txt"); body != "b" || res.Header.Get("Cache-Control") != "max-age=60" {
+		t.Errorf("bad asset response: %q %v", body, res.Header)
+	}
+	if _, body := get("/a"); !strings.Contains(body, `href="b.txt"`) {
+		t.Errorf("bad listing: %s", body)
+	}
+
+	var entries []AssetMeta
+	if _, body := get("/", "Accept", "application/json"); json.Unmarshal([]byte(body), &entries) != nil || len(entries) != 2 {
+		t.Errorf("bad JSON listing: %s", body)
+	}
+
+	var meta AssetMeta
+	if _, body := get(BrowseMetaPath + "l.txt"); json.Unmarshal([]byte(body), &meta) != nil {
+		t.Fatalf("bad meta: %s", body)
+	}
+	if meta.Link != "a/b.txt" || meta.Size != 1 || meta.Headers.Get("Cache-Control") != "max-age=60" {
+		t.Errorf("bad meta: %+v", meta)
+	}
+	if res, _ := get(BrowseMetaPath + "x"); res.StatusCode != http.StatusNotFound {
+		t.Errorf("have status %d, want 404", res.StatusCode)
+	}
+}
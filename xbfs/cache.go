@@ -0,0 +1,58 @@
+package xbfs
+
+import (
+	"errors"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+// SetCache sets the data cache of files of root file system, shared by the
+// name spaces. Must be set before the concurrent use of file system. See
+// xbcommon.DataCache.
+func (fs *FileSystem) SetCache(cache *xbcommon.DataCache) *FileSystem {
+	if fs.root != nil {
+		fs.root.SetCache(cache)
+		return fs
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.cache = cache
+	setCache(fs.assets, cache)
+	return fs
+}
+
+// Cache returns the data cache of root file system, if any.
+func (fs *FileSystem) Cache() *xbcommon.DataCache {
+	if fs.root != nil {
+		return fs.root.Cache()
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.cache
+}
+
+// Preload warms up the data cache with the files matching any of glob
+// patterns, relative to file system. See xbcommon.DataCache.Preload.
+func (fs *FileSystem) Preload(patterns ...string) (count int, err error) {
+	var cache = fs.Cache()
+	if cache == nil {
+		return 0, errors.New("file system without data cache")
+	}
+	var dir = fs.tree()
+	if fs.path != "" {
+		if dir, err = dir.GetDir(fs.path); err != nil {
+			return
+		}
+	}
+	return cache.Preload(dir, patterns...)
+}
+
+// setCache sets the data cache of files of tree.
+func setCache(tree xbcommon.NodeDir, cache *xbcommon.DataCache) {
+	tree.Walk(func(_, _ string, n xbcommon.Node, _ interface{}) (interface{}, error) {
+		if f, ok := n.(*xbcommon.File); ok {
+			f.SetCache(cache)
+		}
+		return nil, nil
+	})
+}
@@ -0,0 +1,155 @@
+package xbfs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// ChangeOp is the operation of the change event.
+type ChangeOp uint8
+
+const (
+	// ChangeAdd is the added asset event.
+	ChangeAdd ChangeOp = iota + 1
+	// ChangeModify is the modified asset event.
+	ChangeModify
+	// ChangeRemove is the removed asset event.
+	ChangeRemove
+)
+
+var changeOpNames = [...]string{ChangeAdd: "add", ChangeModify: "modify", ChangeRemove: "remove"}
+
+func (op ChangeOp) String() string {
+	if int(op) < len(changeOpNames) && changeOpNames[op] != "" {
+		return changeOpNames[op]
+	}
+	return fmt.Sprintf("ChangeOp(%d)", op)
+}
+
+func (op ChangeOp) MarshalText() ([]byte, error) {
+	return []byte(op.String()), nil
+}
+
+// ChangeEvent is the change event of asset, published by the dev mode file
+// systems. See Watch.
+type ChangeEvent struct {
+	Op   ChangeOp `json:"op"`
+	Name string   `json:"name"`
+}
+
+func (e ChangeEvent) String() string {
+	return e.Op.String() + " " + e.Name
+}
+
+// Subscribe registers the change events callback and returns the
+// unsubscribe func. The callback of name spaced file system receives only
+// the events of assets into the name space, with the name relative to it.
+// The callback is called by the publisher goroutine, so it must not block.
+func (fs *FileSystem) Subscribe(cb func(e ChangeEvent)) (unsubscribe func()) {
+	if prefix := fs.path; prefix != "" {
+		prefix = strings.Trim(path.Clean(prefix), "/") + "/"
+		next := cb
+		cb = func(e ChangeEvent) {
+			if strings.HasPrefix(e.Name, prefix) {
+				e.Name = strings.TrimPrefix(e.Name, prefix)
+				next(e)
+			}
+		}
+	}
+
+	root := fs
+	if fs.root != nil {
+		root = fs.root
+	}
+
+	root.mu.Lock()
+	defer root.mu.Unlock()
+	if root.subscribers == nil {
+		root.subscribers = map[int]func(e ChangeEvent){}
+	}
+	root.subscriberID++
+	id := root.subscriberID
+	root.subscribers[id] = cb
+
+	return func() {
+		root.mu.Lock()
+		defer root.mu.Unlock()
+		delete(root.subscribers, id)
+	}
+}
+
+// Publish calls the subscribers with the change event. The event name is
+// relative to the root file system.
+func (fs *FileSystem) Publish(e ChangeEvent) {
+	if fs.root != nil {
+		fs.root.Publish(e)
+		return
+	}
+	fs.mu.RLock()
+	subscribers := make([]func(e ChangeEvent), 0, len(fs.subscribers))
+	for _, cb := range fs.subscribers {
+		subscribers = append(subscribers, cb)
+	}
+	fs.mu.RUnlock()
+
+	for _, cb := range subscribers {
+		cb(e)
+	}
+}
+
+// EventsHandler returns the Server-Sent Events handler of the change events,
+// for the browser live reload. Each event is sent as `change` event, with
+// the JSON encoded ChangeEvent as data:
+//
+//	http.Handle("/xb-events", fs.EventsHandler())
+//
+//	new EventSource("/xb-events").addEventListener("change", function (e) {
+//		console.log(JSON.parse(e.data).name);
+//		location.reload();
+//	});
+//
+// The events are dropped while the client is slow to read them.
+func (fs *FileSystem) EventsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		events := make(chan ChangeEvent, 64)
+		unsubscribe := fs.Subscribe(func(e ChangeEvent) {
+			select {
+			case events <- e:
+			default:
+			}
+		})
+		defer unsubscribe()
+
+		header := w.Header()
+		header.Set("Content-Type", "text/event-stream")
+		header.Set("Cache-Control", "no-cache")
+		header.Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case e := <-events:
+				data, err := json.Marshal(e)
+				if err != nil {
+					return
+				}
+				if _, err = fmt.Fprintf(w, "event: change\ndata: %s\n\n", data); err != nil {
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/moisespsena-go/assetfs/local"
 
@@ -34,6 +35,15 @@ type FileSystem struct {
 	HttpHandler http.Handler
 	notExists   bool
 
+	// NameResolver resolves the hashed and original asset names.
+	NameResolver xbcommon.NameResolver
+
+	// mu guards the assets and the subscribers of root file system.
+	mu           sync.RWMutex
+	subscribers  map[int]func(e ChangeEvent)
+	subscriberID int
+	cache        *xbcommon.DataCache
+
 	local.LocalSourcesAttribute
 }
 
@@ -78,6 +88,64 @@ func (fs *FileSystem) init() {
 	}
 }
 
+// SetNameResolver sets the name resolver and returns the file system.
+func (fs *FileSystem) SetNameResolver(r xbcommon.NameResolver) *FileSystem {
+	fs.NameResolver = r
+	return fs
+}
+
+func (fs *FileSystem) nameResolver() xbcommon.NameResolver {
+	if fs.NameResolver == nil && fs.root != nil {
+		return fs.root.NameResolver
+	}
+	return fs.NameResolver
+}
+
+// tree returns the assets tree of root file system.
+func (fs *FileSystem) tree() xbcommon.NodeDir {
+	if fs.root != nil {
+		return fs.root.tree()
+	}
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+	return fs.assets
+}
+
+// SetTree replaces the assets tree of root file system. Used by the dev
+// mode file systems on local files changes. See Watch.
+func (fs *FileSystem) SetTree(assets xbcommon.NodeDir) {
+	if fs.root != nil {
+		fs.root.SetTree(assets)
+		return
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.assets = assets
+	if fs.cache != nil {
+		setCache(assets, fs.cache)
+	}
+}
+
+// find finds the node by name, following the symlinks. If not found, tries
+// the hashed or original name of asset. Returns a *xbcommon.LinkError if a
+// symlink target escapes the assets root.
+func (fs *FileSystem) find(name string) (node xbcommon.Node, err error) {
+	var assets = fs.tree()
+	if node, err = xbcommon.ResolveLink(assets, name); node == nil {
+		if _, ok := err.(*xbcommon.LinkError); ok {
+			return
+		}
+		if r := fs.nameResolver(); r != nil {
+			if alt := r.HashedName(name); alt != name {
+				node, err = xbcommon.ResolveLink(assets, alt)
+			} else if alt = r.Original(name); alt != name {
+				node, err = xbcommon.ResolveLink(assets, alt)
+			}
+		}
+	}
+	return
+}
+
 func (fs *FileSystem) GetPath() string {
 	return fs.path
 }
@@ -136,7 +204,7 @@ func (fs *FileSystem) NameSpace(nameSpace string) assetfsapi.NameSpacedInterface
 			} else {
 				path = filepath.Join(fs.path, path)
 			}
-			ns = &FileSystem{assets: fs.assets, path: path, root: root, parent: fs, nameSpace: name}
+			ns = &FileSystem{path: path, root: root, parent: fs, nameSpace: name}
 			ns.init()
 			fs.nameSpaces[nameSpace] = ns
 		}
@@ -156,7 +224,7 @@ func (fs *FileSystem) GetParent() assetfsapi.Interface {
 
 func (fs *FileSystem) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if fs.HttpHandler == nil {
-		fs.HttpHandler = assetfs.HttpStaticHandler(fs)
+		fs.HttpHandler = NewMetadataHandler(fs, assetfs.HttpStaticHandler(fs))
 	}
 	fs.HttpHandler.ServeHTTP(w, r)
 }
@@ -13,7 +13,7 @@ import (
 
 // Names list matched files from assetfs
 func glob(fs *FileSystem, pattern assetfsapi.GlobPattern, cb func(pth string, isDir bool) error) (err error) {
-	var root = fs.assets
+	var root = fs.tree()
 	if fs.root != nil {
 		if root, err = root.GetDir(pattern.Dir()); err != nil {
 			return
@@ -26,7 +26,7 @@ func glob(fs *FileSystem, pattern assetfsapi.GlobPattern, cb func(pth string, is
 
 // Names list matched files from assetfs
 func globInfo(fs *FileSystem, pattern assetfsapi.GlobPattern, cb func(info assetfsapi.FileInfo) error) (err error) {
-	var root = fs.assets
+	var root = fs.tree()
 	if fs.path != "" {
 		pattern = pattern.Wrap(fs.path)
 	}
@@ -37,7 +37,11 @@ func asset(fs *FileSystem, ctx context.Context, name string) ([]byte, error) {
 	if fs.root != nil {
 		name = path.Join(fs.path, name)
 	}
-	if asset := fs.assets.Asset(name); asset == nil {
+	node, err := fs.find(name)
+	if err != nil {
+		return nil, err
+	}
+	if asset, ok := node.(xbcommon.Asset); !ok {
 		return nil, oscommon.ErrNotFound(name)
 	} else {
 		return asset.Data()
@@ -48,17 +52,18 @@ func assetInfo(fs *FileSystem, _ context.Context, name string) (_ assetfsapi.Fil
 	if fs.root != nil {
 		name = path.Join(fs.path, name)
 	}
-	if node := fs.assets.Find(name); node != nil {
-		if dir, ok := node.(xbcommon.NodeDir); ok {
-			return NewDirInfo(dir, name), nil
-		}
-		return NewFileInfo(node.(xbcommon.Asset), name), nil
+	node, err := fs.find(name)
+	if err != nil {
+		return nil, err
+	}
+	if dir, ok := node.(xbcommon.NodeDir); ok {
+		return NewDirInfo(dir, name), nil
 	}
-	return nil, oscommon.ErrNotFound(name)
+	return NewFileInfo(node.(xbcommon.Asset), name), nil
 }
 
 func readDir(fs *FileSystem, dir string, cb assetfsapi.CbWalkInfoFunc, skipDir bool) (err error) {
-	var n = fs.assets
+	var n = fs.tree()
 
 	if n, err = n.GetDir(dir); err != nil {
 		return
@@ -81,7 +86,7 @@ func readDir(fs *FileSystem, dir string, cb assetfsapi.CbWalkInfoFunc, skipDir b
 }
 
 func walk(fs *FileSystem, dir string, cb assetfsapi.CbWalkFunc, mode assetfsapi.WalkMode) (err error) {
-	var n = fs.assets
+	var n = fs.tree()
 
 	if dir == "" {
 		dir = "."
@@ -107,7 +112,7 @@ func walk(fs *FileSystem, dir string, cb assetfsapi.CbWalkFunc, mode assetfsapi.
 }
 
 func walkInfo(fs *FileSystem, dir string, cb assetfsapi.CbWalkInfoFunc, mode assetfsapi.WalkMode) (err error) {
-	var n = fs.assets
+	var n = fs.tree()
 
 	if n, err = n.GetDir(dir); err != nil {
 		return
@@ -0,0 +1,52 @@
+package xbfs
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/moisespsena-go/assetfs"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+// MetadataHandler sets the response headers from the asset metadata, and
+// calls the Handler. The `content-type` metadata is the Content-Type header,
+// so http.ServeContent does not sniff the contents at request time. The
+// `header.NAME` metadata values are sent as NAME headers.
+type MetadataHandler struct {
+	Handler http.Handler
+	FS      *FileSystem
+}
+
+// NewMetadataHandler wraps handler into a MetadataHandler.
+func NewMetadataHandler(fs *FileSystem, handler http.Handler) *MetadataHandler {
+	return &MetadataHandler{handler, fs}
+}
+
+func (h *MetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.FS.metadataHeaders(r.Context(), r.URL.Path, w.Header())
+	h.Handler.ServeHTTP(w, r)
+}
+
+// metadataHeaders sets the header from the metadata of asset of request
+// path pth.
+func (fs *FileSystem) metadataHeaders(ctx context.Context, pth string, header http.Header) {
+	if fspath := assetfs.RootPath(fs); fspath != "" {
+		pth = strings.TrimPrefix(pth, fspath)
+	}
+	pth = strings.TrimPrefix(pth, "/")
+
+	if info, err := fs.AssetInfoC(ctx, pth); err == nil {
+		if asset, ok := info.(*FileInfo); ok {
+			for key, value := range asset.Metadata() {
+				if strings.HasPrefix(key, xbcommon.MetadataHeaderPrefix) {
+					header.Set(strings.TrimPrefix(key, xbcommon.MetadataHeaderPrefix), value)
+				}
+			}
+			if ct := asset.ContentType(); ct != "" {
+				header.Set("Content-Type", ct)
+			}
+		}
+	}
+}
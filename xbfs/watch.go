@@ -0,0 +1,288 @@
+package xbfs
+
+import (
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+// WatchDelay is the quiet time after the last local file change before the
+// Watcher replaces the assets tree and publishes the changes, so the
+// editors writes are published once.
+var WatchDelay = 100 * time.Millisecond
+
+// Watcher watches the local files of dev mode file system. Created by Watch.
+type Watcher struct {
+	fs      *FileSystem
+	watcher *fsnotify.Watcher
+
+	mu      sync.Mutex
+	files   map[string]string // local file path: asset name
+	dirs    map[string]string // local directory: assets directory name
+	ignored map[string]bool   // the local non asset files at start
+	pending map[string]ChangeOp
+	timer   *time.Timer
+	done    chan struct{}
+}
+
+// Watch watches the directories of local files and, on changes, replaces
+// the assets tree of fs by the local files tree and publishes the change
+// events. The files maps the local file path to the asset name.
+//
+// The new files into the watched directories, or into its new sub
+// directories, are added with the name relative to the assets directory.
+// The hidden and backup (`~` suffixed) files, and the other files existing
+// at start, are ignored.
+func Watch(fs *FileSystem, files map[string]string) (w *Watcher, err error) {
+	w = &Watcher{
+		fs:      fs,
+		files:   map[string]string{},
+		dirs:    map[string]string{},
+		ignored: map[string]bool{},
+		pending: map[string]ChangeOp{},
+		done:    make(chan struct{}),
+	}
+	if w.watcher, err = fsnotify.NewWatcher(); err != nil {
+		return nil, err
+	}
+
+	for pth, name := range files {
+		pth = filepath.Clean(pth)
+		w.files[pth] = name
+		// maps the directories while both paths have parents
+		for dir, dirName := filepath.Dir(pth), path.Dir(name); ; dir, dirName = filepath.Dir(dir), path.Dir(dirName) {
+			if _, ok := w.dirs[dir]; ok {
+				break
+			}
+			w.dirs[dir] = dirName
+			if dirName == "." || dir == filepath.Dir(dir) {
+				break
+			}
+		}
+	}
+
+	for dir := range w.dirs {
+		if err = w.watcher.Add(dir); err != nil {
+			w.watcher.Close()
+			return nil, err
+		}
+		if entries, err := readDirNames(dir); err == nil {
+			for _, name := range entries {
+				if pth := filepath.Join(dir, name); w.files[pth] == "" {
+					w.ignored[pth] = true
+				}
+			}
+		}
+	}
+
+	go w.run()
+	return
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	select {
+	case <-w.done:
+		return nil
+	default:
+	}
+	close(w.done)
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	return w.watcher.Close()
+}
+
+func (w *Watcher) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case e, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			w.handle(e)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("WARNING: xbfs watch: %v", err)
+		}
+	}
+}
+
+func (w *Watcher) handle(e fsnotify.Event) {
+	pth := filepath.Clean(e.Name)
+	if base := filepath.Base(pth); strings.HasPrefix(base, ".") || strings.HasSuffix(base, "~") {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	switch {
+	case e.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		if name, ok := w.files[pth]; ok {
+			delete(w.files, pth)
+			w.change(name, ChangeRemove)
+		} else if _, ok := w.dirs[pth]; ok {
+			w.removeDir(pth)
+		}
+	case e.Op&fsnotify.Create != 0:
+		if name, ok := w.files[pth]; ok {
+			// replaced by rename
+			w.change(name, ChangeModify)
+			return
+		}
+		if w.ignored[pth] {
+			return
+		}
+		dirName, ok := w.dirs[filepath.Dir(pth)]
+		if !ok {
+			return
+		}
+		info, err := os.Stat(pth)
+		if err != nil {
+			return
+		}
+		name := path.Join(dirName, filepath.Base(pth))
+		if info.IsDir() {
+			w.addDir(pth, name)
+		} else {
+			w.files[pth] = name
+			w.change(name, ChangeAdd)
+		}
+	case e.Op&fsnotify.Write != 0:
+		if name, ok := w.files[pth]; ok {
+			w.change(name, ChangeModify)
+		}
+	}
+}
+
+// addDir watches the new directory and adds its files.
+func (w *Watcher) addDir(dir, dirName string) {
+	if err := w.watcher.Add(dir); err != nil {
+		log.Printf("WARNING: xbfs watch: %v", err)
+		return
+	}
+	w.dirs[dir] = dirName
+	names, _ := readDirNames(dir)
+	for _, base := range names {
+		if strings.HasPrefix(base, ".") || strings.HasSuffix(base, "~") {
+			continue
+		}
+		var (
+			pth  = filepath.Join(dir, base)
+			name = path.Join(dirName, base)
+		)
+		if info, err := os.Stat(pth); err != nil {
+			continue
+		} else if info.IsDir() {
+			w.addDir(pth, name)
+		} else if _, ok := w.files[pth]; !ok {
+			w.files[pth] = name
+			w.change(name, ChangeAdd)
+		}
+	}
+}
+
+// removeDir removes the files of removed directory and its sub directories.
+func (w *Watcher) removeDir(dir string) {
+	prefix := dir + string(filepath.Separator)
+	for pth, name := range w.files {
+		if strings.HasPrefix(pth, prefix) {
+			delete(w.files, pth)
+			w.change(name, ChangeRemove)
+		}
+	}
+	for pth := range w.dirs {
+		if pth == dir || strings.HasPrefix(pth, prefix) {
+			delete(w.dirs, pth)
+		}
+	}
+}
+
+// change coalesces the change of asset with the pending change and
+// schedules the publishing.
+func (w *Watcher) change(name string, op ChangeOp) {
+	switch prev := w.pending[name]; {
+	case prev == ChangeAdd && op == ChangeModify:
+		op = ChangeAdd
+	case prev == ChangeAdd && op == ChangeRemove:
+		delete(w.pending, name)
+		op = 0
+	case prev == ChangeRemove && op == ChangeAdd:
+		op = ChangeModify
+	}
+	if op != 0 {
+		w.pending[name] = op
+	}
+
+	if w.timer == nil {
+		w.timer = time.AfterFunc(WatchDelay, w.flush)
+	} else {
+		w.timer.Reset(WatchDelay)
+	}
+}
+
+// flush replaces the assets tree and publishes the pending changes.
+func (w *Watcher) flush() {
+	w.mu.Lock()
+	select {
+	case <-w.done:
+		w.mu.Unlock()
+		return
+	default:
+	}
+
+	// the files removed without event, like the broken symlinks
+	for pth, name := range w.files {
+		if _, err := os.Stat(pth); err != nil {
+			delete(w.files, pth)
+			w.change(name, ChangeRemove)
+		}
+	}
+
+	var events = make([]ChangeEvent, 0, len(w.pending))
+	for name, op := range w.pending {
+		events = append(events, ChangeEvent{Op: op, Name: name})
+	}
+	w.pending = map[string]ChangeOp{}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Name < events[j].Name
+	})
+
+	tree, err := xbcommon.NewLocalTree(w.files)
+	w.mu.Unlock()
+
+	if err != nil {
+		log.Printf("WARNING: xbfs watch: %v", err)
+		return
+	}
+	w.fs.SetTree(tree.Root())
+
+	for _, e := range events {
+		w.fs.Publish(e)
+	}
+}
+
+func readDirNames(dir string) ([]string, error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Readdirnames(-1)
+}
@@ -0,0 +1,100 @@
+package xbfs
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/moisespsena-go/xbindata/xbcommon"
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xbfs-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, data string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.txt", "a")
+	write("ignored.txt", "i")
+
+	files := map[string]string{filepath.Join(dir, "a.txt"): "a.txt"}
+	tree, err := xbcommon.NewLocalTree(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fs := NewFileSystem(tree.Root())
+	w, err := Watch(fs, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	srv := httptest.NewServer(fs.EventsHandler())
+	defer srv.Close()
+	res, err := srv.Client().Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("bad content type %q", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+				lines <- strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+
+	expect := func(want string) {
+		select {
+		case have := <-lines:
+			if have != want {
+				t.Fatalf("have event %s, want %s", have, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting event %s", want)
+		}
+	}
+
+	write("a.txt", "changed")
+	write("ignored.txt", "changed")
+	expect(`{"op":"modify","name":"a.txt"}`)
+	if asset, err := fs.Asset("a.txt"); err != nil {
+		t.Fatal(err)
+	} else if data, err := asset.DataS(); err != nil || data != "changed" {
+		t.Fatalf("have %q %v", data, err)
+	}
+
+	if err = os.Mkdir(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write("sub/b.txt", "b")
+	expect(`{"op":"add","name":"sub/b.txt"}`)
+	if _, err = fs.AssetInfo("sub/b.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = os.Remove(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	expect(`{"op":"remove","name":"a.txt"}`)
+	if _, err = fs.AssetInfo("a.txt"); err == nil {
+		t.Fatal("a.txt not removed")
+	}
+}